@@ -0,0 +1,42 @@
+package iocgo
+
+// NewChild 创建一个子容器，子容器中找不到的binding会到父容器中找。
+// 子容器可以覆盖父容器中的部分binding，父容器中的单例仍然是共享的，
+// 但如果父容器中注册的构造函数依赖了子容器中覆盖的binding，通过子容器获得时会在子容器中单独构造
+func (c *Container) NewChild() *Container {
+	child := NewContainer()
+	child.parent = c
+	return child
+}
+
+// isAncestorOf 判断c是不是other本身或者other的祖先容器
+func (c *Container) isAncestorOf(other *Container) bool {
+	for cur := other; cur != nil; cur = cur.parent {
+		if cur == c {
+			return true
+		}
+	}
+	return false
+}
+
+// overrides 判断通过c获得binding b时，b的依赖中是否有被c或者c与b所在容器之间的容器覆盖的binding
+func (c *Container) overrides(b *binding) bool {
+	return c.overridesWith(b, b.owner, make(map[*binding]bool))
+}
+
+func (c *Container) overridesWith(b *binding, owner *Container, visited map[*binding]bool) bool {
+	if visited[b] {
+		return false
+	}
+	visited[b] = true
+	resolver := b.resolver(c)
+	for _, dep := range b.dependencies(resolver) {
+		dbs, _ := resolver.dependencyBindings(dep)
+		for _, db := range dbs {
+			if !db.owner.isAncestorOf(owner) || c.overridesWith(db, owner, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
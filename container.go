@@ -1,40 +1,76 @@
 package iocgo
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"reflect"
 	"strings"
+	"sync"
 	"unsafe"
 )
 
-var (
-	errNotFound = errors.New("not found")
-)
-
 type binding struct {
 	specifiedParameters map[int]interface{} //构造对象时参数指定的值
 	dependsOn           map[int]string      //构造对象时依赖的其他对象的name
+	groupDependsOn      map[int]string      //构造对象时依赖的分组的名字
+	values              map[int]string      //构造对象时注入的配置项的key
 	constructor         interface{}         //构造函数指针，用于构造对应的实例
 	instance            interface{}         //在默认单例情况下，存储对应的绑定的实例
+	decorated           bool                //通过RegisterInstance注册的实例是否已经应用了装饰器
 	isTransient         bool                //是否是临时对象
+	isScoped            bool                //是否是作用域对象，在每个Scope中只构造一次
 	isDefault           bool                //是否是默认对象
 	name                string              //对应的名字
 	resolveTypes        []reflect.Type      //指定构造函数返回的参数列表对应的接口类型，如果不指定某个返回值，可以设置为nil
 	optionalIndexes     map[int]bool        //哪些参数是可选的，如果可选，那么即使无法找到对应实例也不会报错
+	externallyOwned     bool                //实例是否由外部管理，如果是，容器Close时不会释放该实例
+	onStart             []Hook              //容器Start时对实例执行的钩子
+	onStop              []Hook              //容器Stop时对实例执行的钩子
+	owner               *Container          //注册该binding的容器
+	abstract            reflect.Type        //binding对应的接口类型
+	groups              []string            //binding所属的分组
+	order               int                 //注入多个binding时的顺序，越小越靠前
+	seq                 uint64              //注册的顺序
+	call                *sharedCall         //同一个构造函数注册的多个binding共享的单例调用结果，为空时不共享
+	output              int                 //binding对应构造函数的第几个返回值
+	field               []int               //返回值是结果对象时，binding对应的字段
+	profiles            []string            //binding所属的profile，为空时总是参与解析
+	conditions          []condition         //binding参与解析的条件
+	exported            bool                //是否是从模块中导出的binding，如果是，总是在模块的私有容器中解析依赖
+	mu                  sync.Mutex          //保证单例的构造函数只执行一次
 }
 
 func (b *binding) Clone() *binding {
+	b.mu.Lock()
+	instance, decorated := b.instance, b.decorated
+	b.mu.Unlock()
 	clone := &binding{
 		specifiedParameters: make(map[int]interface{}, len(b.specifiedParameters)),
 		dependsOn:           make(map[int]string, len(b.dependsOn)),
+		groupDependsOn:      b.groupDependsOn,
+		values:              b.values,
 		constructor:         b.constructor,
-		instance:            b.instance,
+		instance:            instance,
+		decorated:           decorated,
 		isTransient:         b.isTransient,
+		isScoped:            b.isScoped,
 		isDefault:           b.isDefault,
 		name:                b.name,
 		resolveTypes:        b.resolveTypes,
 		optionalIndexes:     make(map[int]bool, len(b.optionalIndexes)),
+		externallyOwned:     b.externallyOwned,
+		onStart:             b.onStart,
+		onStop:              b.onStop,
+		owner:               b.owner,
+		abstract:            b.abstract,
+		groups:              b.groups,
+		order:               b.order,
+		seq:                 b.seq,
+		call:                b.call,
+		output:              b.output,
+		field:               b.field,
+		profiles:            b.profiles,
+		conditions:          b.conditions,
+		exported:            b.exported,
 	}
 	for k, v := range b.specifiedParameters {
 		clone.specifiedParameters[k] = v
@@ -49,23 +85,133 @@ func (b *binding) Clone() *binding {
 }
 
 // resolve creates an appropriate implementation of the related abstraction
-func (b *binding) resolve(c *Container) (interface{}, error) {
+func (b *binding) resolve(c *Container, r *resolution) (interface{}, error) {
+	return b.resolveWith(c, r, b.specifiedParameters)
+}
+
+// resolveWith 使用指定的构造函数参数值获得binding对应的实例，
+// 单例即使在并发获取时也只会被构造一次
+func (b *binding) resolveWith(c *Container, r *resolution, parameters map[int]interface{}) (interface{}, error) {
+	c.notify(b)
+	c = b.resolver(c)
+	next, err := r.enter(b)
+	if err != nil {
+		return nil, newResolutionError(r.path, "", err)
+	}
+	r = next
+	if r.ctx != nil && r.ctx.Err() != nil { //调用者取消或者超时后不再构造新的实例
+		return nil, newResolutionError(r.path, "", r.ctx.Err())
+	}
+	switch {
+	case b.isScoped:
+		if r.scope == nil {
+			return nil, newResolutionError(r.path, "", ErrNoScope)
+		}
+		return r.scope.slot(b).get(func() (interface{}, error) {
+			return b.create(c, r, parameters, r.scope.sharedCall(b), r.scope.track)
+		})
+	case b.isTransient:
+		return b.create(c, r, parameters, nil, nil)
+	case c != b.owner && c.overrides(b):
+		//通过子容器获得父容器中的单例，而其依赖在子容器中被覆盖了，那么在子容器中构造一个单独的实例
+		return c.slot(b).get(func() (interface{}, error) {
+			return b.create(c, r.withoutScope(), parameters, c.overriddenCall(b), c.track)
+		})
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	if b.instance != nil {
-		return b.instance, nil
+		if b.constructor != nil || b.decorated {
+			return b.instance, nil
+		}
+		//通过RegisterInstance注册的实例在第一次获得时应用装饰器
+		inst, err := c.decorate(r.withoutScope(), b, b.instance)
+		if err != nil {
+			return nil, err
+		}
+		b.instance, b.decorated = inst, true
+		return inst, nil
+	}
+	//单例的依赖不能是作用域对象，否则作用域结束后单例仍然持有该对象
+	inst, err := b.create(c, r.withoutScope(), parameters, b.call, b.owner.track)
+	if err != nil {
+		return nil, err
+	}
+	b.instance = inst
+	return inst, nil
+}
+
+// create 构造一个新的实例并应用装饰器，call不为空时与同一个构造函数注册的其他binding共享这次调用的结果，
+// track不为空时用来记录构造函数创建的原始实例，这样生命周期钩子和Close处理的总是原始实例
+func (b *binding) create(c *Container, r *resolution, parameters map[int]interface{}, call *sharedCall,
+	track func(b *binding, instance interface{})) (interface{}, error) {
+	inst, err := b.construct(c, r, parameters, call)
+	if err != nil {
+		return nil, err
 	}
+	decorated, err := c.decorate(r, b, inst)
+	if err != nil {
+		return nil, err
+	}
+	if track != nil {
+		track(b, inst)
+	}
+	return decorated, nil
+}
 
-	instList, err := c.invoke(b.constructor, b.specifiedParameters, b.dependsOn, b.optionalIndexes)
+// construct 调用构造函数创建一个新的实例
+func (b *binding) construct(c *Container, r *resolution, parameters map[int]interface{}, shared *sharedCall) (interface{}, error) {
+	defer r.finish()
+	call := func() ([]interface{}, error) {
+		args, err := c.arguments(r, b.constructor, parameters, b.injection)
+		if err != nil {
+			return nil, err
+		}
+		instList, err := callFunction(b.constructor, args)
+		if err != nil { //构造函数返回的error
+			return nil, newResolutionError(r.path, "", err)
+		}
+		return instList, nil
+	}
+	var instList []interface{}
+	var err error
+	if shared != nil {
+		instList, err = shared.get(call)
+	} else {
+		instList, err = call()
+	}
 	if err != nil {
 		return nil, err
 	}
 	if len(instList) == 0 {
-		return nil, errors.New("resolve function must return instance")
+		return nil, newResolutionError(r.path, "", containerError(ErrInvalidConstructor, "resolve function must return instance"))
+	}
+	return b.result(instList), nil
+}
+
+// instanceSlot 保存一个在某个范围内只构造一次的实例
+type instanceSlot struct {
+	mu       sync.Mutex
+	instance interface{}
+	done     bool
+}
+
+func (s *instanceSlot) get(construct func() (interface{}, error)) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return s.instance, nil
 	}
-	inst := instList[0]
-	if !b.isTransient {
-		b.instance = inst
+	inst, err := construct()
+	if err != nil {
+		return nil, err
 	}
-	return inst, err
+	s.instance, s.done = inst, true
+	return inst, nil
+}
+
+func (b *binding) String() string {
+	return describe(b.abstract, b.name)
 }
 
 type namedBinding struct {
@@ -75,11 +221,28 @@ type namedBinding struct {
 
 func (b *namedBinding) Clone() *namedBinding {
 	clone := &namedBinding{
-		defaultBinding: b.defaultBinding.Clone(),
-		namedBinding:   make(map[string]*binding, len(b.namedBinding)),
+		namedBinding: make(map[string]*binding, len(b.namedBinding)),
 	}
 	for k, v := range b.namedBinding {
 		clone.namedBinding[k] = v.Clone()
+		if v == b.defaultBinding {
+			clone.defaultBinding = clone.namedBinding[k]
+		}
+	}
+	if clone.defaultBinding == nil {
+		clone.defaultBinding = b.defaultBinding.Clone()
+	}
+	return clone
+}
+
+// copy 浅复制namedBinding，不复制其中的binding
+func (b *namedBinding) copy() *namedBinding {
+	clone := &namedBinding{
+		defaultBinding: b.defaultBinding,
+		namedBinding:   make(map[string]*binding, len(b.namedBinding)),
+	}
+	for k, v := range b.namedBinding {
+		clone.namedBinding[k] = v
 	}
 	return clone
 }
@@ -97,58 +260,116 @@ func (nb *namedBinding) addNewBinding(b *binding, isDefault bool) {
 
 // Container interface类型->map["name"]binding对象，如果没有命名实例，那么name就是""
 type Container struct {
-	bind  map[reflect.Type]*namedBinding
-	alias map[reflect.Type]reflect.Type
+	bind            map[reflect.Type]*namedBinding
+	alias           map[reflect.Type]reflect.Type
+	parent          *Container                  //父容器，在本容器中找不到的binding会到父容器中找
+	overridden      map[*binding]*instanceSlot  //父容器中的单例因为依赖被本容器覆盖而在本容器中单独构造的实例
+	overriddenCalls map[*sharedCall]*sharedCall //与overridden对应，同一个构造函数注册的多个binding在本容器中单独构造时共享的调用结果
+	decorators      map[reflect.Type][]*binding //通过Decorate注册的装饰器，按注册顺序排列
+	groups          map[string][]*binding       //分组名->分组中的binding，按注册顺序排列
+	properties      []PropertySource            //配置项的来源，后添加的优先
+	profiles        []string                    //通过ActivateProfiles激活的profile
+	conditional     map[reflect.Type][]*binding //带有Profile或者条件的binding，按注册顺序排列
+	modules         map[string]*Container       //已经安装的模块名->模块的私有容器
+	tracker         *Container                  //模块的私有容器将实例记录到安装模块的容器中，由其管理生命周期
+	observers       []*observer                 //通过Observe注册的观察者
+	mu              sync.RWMutex                //保护bind、alias、overridden、decorators、groups、properties、profiles、conditional、modules和observers
+	resolved        []resolvedInstance          //按创建顺序记录容器持有的单例实例，用于Close时逆序释放
+	started         []resolvedInstance          //已经执行过Start钩子的实例，用于Stop时逆序停止
+	nStarted        int                         //resolved中已经被Start处理过的实例数量
+	lifeMu          sync.Mutex                  //保护resolved、started和nStarted
 }
 
 // NewContainer creates a new instance of the Container
 func NewContainer() *Container {
 	return &Container{
-		bind:  make(map[reflect.Type]*namedBinding),
-		alias: make(map[reflect.Type]reflect.Type),
+		bind:            make(map[reflect.Type]*namedBinding),
+		alias:           make(map[reflect.Type]reflect.Type),
+		overridden:      make(map[*binding]*instanceSlot),
+		overriddenCalls: make(map[*sharedCall]*sharedCall),
+		decorators:      make(map[reflect.Type][]*binding),
+		groups:          make(map[string][]*binding),
+		conditional:     make(map[reflect.Type][]*binding),
+		modules:         make(map[string]*Container),
 	}
 }
 
 //Register 注册一个对象的构造函数到容器中，该构造函数接收其他interface对象或者值对象作为参数，返回interface对象
 //注意返回的应该是interface，而不应该是具体的struct类型的指针
+//构造函数有多个返回值时，每个返回值注册为一个binding，单例只调用一次构造函数，返回的error不会注册为binding
 func (c *Container) Register(constructor interface{}, options ...Option) error {
 	//检查resolver必须是一个构造函数
 	reflectedResolver := reflect.TypeOf(constructor)
-	if reflectedResolver.Kind() != reflect.Func {
-		return errors.New("container: the constructor must be a function")
+	if reflectedResolver == nil || reflectedResolver.Kind() != reflect.Func {
+		return containerError(ErrInvalidConstructor, "the constructor must be a function")
 	}
 	//遍历构造函数的输出，找到具体构造的类型，并将这些类型放入到container中
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call := &sharedCall{}
 	for i := 0; i < reflectedResolver.NumOut(); i++ {
 		//构造新的binding对象
-		b := &binding{constructor: constructor, specifiedParameters: make(map[int]interface{})}
-		for _, op := range options {
-			err := op(b)
+		newBinding := func() (*binding, error) {
+			b := &binding{constructor: constructor, specifiedParameters: make(map[int]interface{}), owner: c, seq: nextRegistration()}
+			for _, op := range options {
+				err := op(b)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return b, nil
+		}
+		resolveType := reflectedResolver.Out(i)
+		if resolveType == errorType { //构造函数返回的error不注册为binding
+			continue
+		}
+		//结果对象的每个字段注册为一个单独的binding
+		if isResultObject(resolveType) {
+			bindings, err := resultBindings(resolveType, i, call, newBinding)
 			if err != nil {
 				return err
 			}
+			for _, b := range bindings {
+				c.addBinding(b)
+			}
+			continue
 		}
-		resolveType := reflectedResolver.Out(i)
+		b, err := newBinding()
+		if err != nil {
+			return err
+		}
+		//构造函数有多个返回值时只调用一次，每个binding获得对应位置的返回值
+		b.call, b.output = call, i
 		if len(b.resolveTypes) > i && b.resolveTypes[i] != nil { //如果指定了映射的interface，则使用指定的
-			if !resolveType.Implements(b.resolveTypes[i]) {
-				return errors.New("resolve type " + resolveType.String() + " not implement " + b.resolveTypes[i].String())
+			if !resolveType.AssignableTo(b.resolveTypes[i]) {
+				return containerError(ErrInvalidAbstraction, "resolve type %s not implement %s", resolveType, b.resolveTypes[i])
 			}
 			resolveType = b.resolveTypes[i]
 		}
-		if namedBinding, has := c.bind[resolveType]; has { //增加新binding
-			namedBinding.addNewBinding(b, b.isDefault)
-		} else { //没有注册过这个接口的任何绑定
-			c.bind[resolveType] = newNamedBinding(b)
-		}
+		b.abstract = resolveType
+		c.addBinding(b)
 	}
 
 	return nil
 }
 
+// addBinding 将binding加入容器，调用者需要持有c.mu
+func (c *Container) addBinding(b *binding) {
+	if b.isConditional() { //带有条件的binding单独存放，解析时再判断是否参与
+		c.conditional[b.abstract] = append(c.conditional[b.abstract], b)
+	} else if namedBinding, has := c.bind[b.abstract]; has { //增加新binding
+		namedBinding.addNewBinding(b, b.isDefault)
+	} else { //没有注册过这个接口的任何绑定
+		c.bind[b.abstract] = newNamedBinding(b)
+	}
+	c.addToGroups(b)
+}
+
 //RegisterInstance 注册一个对象的实例到容器中
 //参数interfacePtr 是一个接口的指针
 //参数instance 是实例值
 func (c *Container) RegisterInstance(interfacePtr interface{}, instance interface{}, options ...Option) error {
-	b := &binding{instance: instance}
+	b := &binding{instance: instance, owner: c, seq: nextRegistration()}
 	for _, op := range options {
 		err := op(b)
 		if err != nil {
@@ -159,11 +380,11 @@ func (c *Container) RegisterInstance(interfacePtr interface{}, instance interfac
 	if err != nil {
 		return err
 	}
-	if namedBinding, has := c.bind[t]; has { //增加新的绑定
-		namedBinding.addNewBinding(b, b.isDefault)
-	} else { //没有注册过这个接口的任何绑定
-		c.bind[t] = newNamedBinding(b)
-	}
+	b.abstract = t
+	c.mu.Lock()
+	c.addBinding(b)
+	c.mu.Unlock()
+	c.track(b, instance)
 	return nil
 }
 
@@ -177,7 +398,9 @@ func (c *Container) RegisterSubInterface(subInterfacePtr interface{}, interfaceP
 	if err != nil {
 		return err
 	}
+	c.mu.Lock()
 	c.alias[stype] = itype
+	c.mu.Unlock()
 	return nil
 }
 
@@ -186,25 +409,27 @@ func (c *Container) SetDefaultBinding(interfacePtr interface{}, defaultName stri
 	if err != nil {
 		return err
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if nameBinding, ok := c.bind[itype]; ok {
 		if theBinding, found := nameBinding.namedBinding[defaultName]; found {
 			nameBinding.defaultBinding = theBinding
 			return nil
 		}
 	}
-	return errNotFound
+	return containerError(ErrNotFound, "no binding found for %s", describe(itype, defaultName))
 }
 func getTypeFromInterface(interfacePtr interface{}) (reflect.Type, error) {
 	ptr := reflect.TypeOf(interfacePtr)
 	if ptr == nil || ptr.Kind() != reflect.Ptr {
-		return nil, errors.New("interfacePtr must be a interface point, not a interface value")
+		return nil, containerError(ErrInvalidAbstraction, "interfacePtr must be a interface point, not a interface value")
 	}
 	return ptr.Elem(), nil
 }
 
-// arguments 通过容器获得一个函数的传入参数的值列表
-func (c *Container) arguments(function interface{}, specifiedParameters map[int]interface{},
-	dependsOn map[int]string, optionalIndexes map[int]bool) ([]reflect.Value, error) {
+// arguments 通过容器获得一个函数的传入参数的值列表，inject返回第i个参数的注入方式
+func (c *Container) arguments(r *resolution, function interface{}, specifiedParameters map[int]interface{},
+	inject func(i int) injection) ([]reflect.Value, error) {
 	reflectedFunction := reflect.TypeOf(function)
 	argumentsCount := reflectedFunction.NumIn()
 	arguments := make([]reflect.Value, argumentsCount)
@@ -219,7 +444,7 @@ func (c *Container) arguments(function interface{}, specifiedParameters map[int]
 			//如果参数是struct类型，需要调用Fill填充这个struct中的字段
 			fieldKind := reflect.TypeOf(specifiedValue).Kind()
 			if fieldKind == reflect.Struct {
-				err := c.Fill(&specifiedValue)
+				err := c.fill(r, &specifiedValue)
 				if err != nil {
 					return nil, err
 				}
@@ -227,7 +452,7 @@ func (c *Container) arguments(function interface{}, specifiedParameters map[int]
 			if fieldKind == reflect.Ptr { //如果是指针，那么获得对应的值类型，判断是否struct，是则Fill
 				elem := reflect.TypeOf(specifiedValue).Elem()
 				if elem.Kind() == reflect.Struct {
-					err := c.Fill(specifiedValue)
+					err := c.fill(r, specifiedValue)
 					if err != nil {
 						return nil, err
 					}
@@ -236,60 +461,151 @@ func (c *Container) arguments(function interface{}, specifiedParameters map[int]
 			arguments[i] = reflect.ValueOf(specifiedValue)
 			continue
 		}
-		name := dependsOn[i]
-		b, err := c.getBinding(abstraction, name)
+		argument, err := c.argument(r, abstraction, inject(i))
 		if err != nil {
-			//找不到该函数对应的参数类型的映射，如果是optional的，则设为空，否则报错
-			if _, optional := optionalIndexes[i]; optional {
-				arguments[i] = reflect.Zero(abstraction)
-				continue
+			return nil, err
+		}
+		arguments[i] = argument
+	}
+	return arguments, nil
+}
+
+// argument 按照inj指定的方式从容器获得类型为abstraction的一个参数的值
+func (c *Container) argument(r *resolution, abstraction reflect.Type, inj injection) (reflect.Value, error) {
+	name, optional := inj.name, inj.optional
+	//注入配置项的值
+	if inj.value != "" {
+		return c.propertyValue(r, abstraction, inj.value, optional)
+	}
+	//依赖分组的参数获得分组中所有的实例
+	if inj.group != "" {
+		return c.groupValue(r, abstraction, inj.group)
+	}
+	//嵌入了In的参数对象，其字段分别从容器获得
+	if isParameterObject(abstraction) {
+		return c.parameterObject(r, abstraction)
+	}
+	//延迟获取的依赖只注入一个句柄，第一次使用时才获得实例
+	if target, ok := c.lazyTarget(abstraction); ok {
+		return c.lazyValue(r, abstraction, target, name), nil
+	}
+	//调用者传入的context.Context注入到该类型的参数
+	if abstraction == contextType && name == "" && r.ctx != nil {
+		return contextValue(r.ctx), nil
+	}
+	b, err := c.getBinding(abstraction, name)
+	if err != nil {
+		//没有传入context.Context也没有注册对应的binding时注入context.Background()
+		if abstraction == contextType && name == "" {
+			return contextValue(context.Background()), nil
+		}
+		//接口的切片或者以名字为key的接口map与Fill一样获得该接口所有的实例
+		if name == "" {
+			collection, ok, err := c.collectionValue(r, abstraction)
+			if err != nil {
+				return reflect.Value{}, err
 			}
-			//必填字段找不到，报错
-			resolveType := ""
-			o := reflectedFunction.Out(0)
-			if o != nil {
-				resolveType = o.String()
+			if ok {
+				return collection, nil
 			}
-			return nil, errors.New("resolve type: " + resolveType + " no concrete found for: " + abstraction.String())
 		}
-
-		instance, err := b.resolve(c)
-		if err != nil {
-			return nil, err
+		//找不到该函数对应的参数类型的映射，如果是optional的，则设为空，否则报错
+		if optional {
+			return reflect.Zero(abstraction), nil
 		}
-		arguments[i] = reflect.ValueOf(instance)
+		//必填字段找不到，报错
+		return reflect.Value{}, newResolutionError(r.path, describe(abstraction, name), ErrNotFound)
 	}
-	return arguments, nil
+
+	instance, err := b.resolve(c, r)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if instance == nil {
+		return reflect.Zero(abstraction), nil
+	}
+	return reflect.ValueOf(instance), nil
 }
 
 func (c *Container) getBinding(theType reflect.Type, name string) (*binding, error) {
-	if namedBinding, exist := c.bind[theType]; exist {
-		//从容器中找到了对应的binding
-		//如果使用DependsOn指定了依赖的对象的name，那么通过指定的name获取binding
-		if name != "" {
-			if b, ok := namedBinding.namedBinding[name]; ok {
-				return b, nil
+	//先在本容器中找，找不到再依次到父容器中找
+	for cur := c; cur != nil; cur = cur.parent {
+		if b := cur.getLocalBinding(theType, name); b != nil {
+			return b, nil
+		}
+	}
+	//没有默认binding时，参与解析的带有条件的binding中先注册的作为默认binding
+	if name == "" {
+		for cur := c; cur != nil; cur = cur.parent {
+			if conditionals := cur.activeConditionals(theType); len(conditionals) > 0 {
+				return conditionals[len(conditionals)-1], nil
 			}
-			return nil, errors.New("container: no concrete found for: " + theType.String() + " name: " + name)
-
 		}
-		//没有通过name指定，那么就取默认的binding
-		return namedBinding.defaultBinding, nil
 	}
 	//找不到该函数对应的参数类型的映射，在alias中找
-	if aType, ok := c.alias[theType]; ok {
-		return c.getBinding(aType, name)
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		aType, ok := cur.alias[theType]
+		cur.mu.RUnlock()
+		if ok {
+			return c.getBinding(aType, name)
+		}
 	}
-	return nil, errNotFound
+	return nil, ErrNotFound
 }
 
-func (c *Container) invoke(function interface{}, specifiedParameters map[int]interface{},
-	dependsOn map[int]string, optionalIndexes map[int]bool) (
-	[]interface{}, error) {
-	args, err := c.arguments(function, specifiedParameters, dependsOn, optionalIndexes)
-	if err != nil {
-		return nil, err
+// getLocalBinding 在本容器中查找binding，找不到时返回nil
+func (c *Container) getLocalBinding(theType reflect.Type, name string) *binding {
+	//参与解析的带有条件的binding优先，没有指定name时取后注册的默认binding
+	for _, b := range c.activeConditionals(theType) {
+		if b.name == name || name == "" && b.isDefault {
+			return b
+		}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	namedBinding, exist := c.bind[theType]
+	if !exist {
+		return nil
+	}
+	//从容器中找到了对应的binding
+	//如果使用DependsOn指定了依赖的对象的name，那么通过指定的name获取binding
+	if name != "" {
+		return namedBinding.namedBinding[name]
+	}
+	//没有通过name指定，那么就取默认的binding
+	return namedBinding.defaultBinding
+}
+
+// slot 获得父容器中的binding在本容器中对应的实例
+func (c *Container) slot(b *binding) *instanceSlot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.overridden[b]
+	if !ok {
+		s = &instanceSlot{}
+		c.overridden[b] = s
 	}
+	return s
+}
+
+// overriddenCall 获得父容器中的binding在本容器中单独构造时，与同一个构造函数注册的其他binding共享的调用结果
+func (c *Container) overriddenCall(b *binding) *sharedCall {
+	if b.call == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.overriddenCalls[b.call]
+	if !ok {
+		s = &sharedCall{}
+		c.overriddenCalls[b.call] = s
+	}
+	return s
+}
+
+// callFunction 使用参数调用函数，如果函数返回了不为空的error，则返回该error
+func callFunction(function interface{}, args []reflect.Value) ([]interface{}, error) {
 	returns := reflect.ValueOf(function).Call(args)
 	if len(returns) == 0 {
 		return nil, nil
@@ -308,9 +624,13 @@ func (c *Container) invoke(function interface{}, specifiedParameters map[int]int
 
 //Resolve input interface, resolve instance. 传入接口的指针，获得对应的实例
 func (c *Container) Resolve(abstraction interface{}, options ...ResolveOption) error {
+	return c.resolve(&resolution{}, abstraction, options...)
+}
+
+func (c *Container) resolve(r *resolution, abstraction interface{}, options ...ResolveOption) error {
 	receiverType := reflect.TypeOf(abstraction)
 	if receiverType == nil {
-		return errors.New("container: invalid abstraction")
+		return containerError(ErrInvalidAbstraction, "abstraction must not be nil")
 	}
 	option := &resolveOption{}
 	for _, op := range options {
@@ -323,36 +643,49 @@ func (c *Container) Resolve(abstraction interface{}, options ...ResolveOption) e
 		elem := receiverType.Elem()
 		b, err := c.getBinding(elem, option.name)
 		if err != nil {
-			return errors.New("resolve type: " + receiverType.String() + " no concrete found for: " + elem.String())
+			return &ResolutionError{Type: elem, Name: option.name, Err: err}
 		}
 
+		//通过Arguments指定的参数只对本次调用有效，不修改binding中注册的参数
 		args := b.specifiedParameters
 		if len(option.args) > 0 {
+			args = make(map[int]interface{}, len(b.specifiedParameters)+len(option.args))
+			for i, v := range b.specifiedParameters {
+				args[i] = v
+			}
 			for i, v := range option.args {
 				args[i] = v
 			}
 		}
-		oldArgs := b.specifiedParameters
-		b.specifiedParameters = args
-		defer func() {
-			b.specifiedParameters = oldArgs
-		}()
-		instance, err := b.resolve(c)
+		instance, err := b.resolveWith(c, r, args)
 		if err != nil {
-			return err //errors.New("resolve type: " + receiverType.String() + " " + err.Error())
+			return withRequest(err, elem, option.name)
 		}
 		reflect.ValueOf(abstraction).Elem().Set(reflect.ValueOf(instance))
 		return nil
 	}
-	return errors.New("container: invalid abstraction")
+	return containerError(ErrInvalidAbstraction, "abstraction must be a interface point, input type: %s", receiverType)
 }
 
 // Call takes a function (receiver) with one or more arguments of the abstractions (interfaces).
 // It invokes the function (receiver) and passes the related implementations.
 func (c *Container) Call(function interface{}, options ...CallOption) ([]interface{}, error) {
+	return c.call(&resolution{}, function, options...)
+}
+
+func (c *Container) call(r *resolution, function interface{}, options ...CallOption) ([]interface{}, error) {
+	args, err := c.callArguments(r, function, options...)
+	if err != nil {
+		return nil, err
+	}
+	return callFunction(function, args)
+}
+
+// callArguments 获得Call传入的函数的参数
+func (c *Container) callArguments(r *resolution, function interface{}, options ...CallOption) ([]reflect.Value, error) {
 	receiverType := reflect.TypeOf(function)
 	if receiverType == nil || receiverType.Kind() != reflect.Func {
-		return nil, errors.New("container: invalid function")
+		return nil, containerError(ErrInvalidFunction, "input type: %v", receiverType)
 	}
 	callOption := &resolveOption{}
 	for _, op := range options {
@@ -361,16 +694,27 @@ func (c *Container) Call(function interface{}, options ...CallOption) ([]interfa
 			return nil, err
 		}
 	}
-	return c.invoke(function, callOption.args, callOption.dependsOn, nil) //TODO optional
-
+	args, err := c.arguments(r, function, callOption.args, callOption.injection) //TODO optional
+	if err != nil {
+		return nil, withRequest(err, receiverType, "")
+	}
+	return args, nil
 }
 
 // Fill takes a struct and resolves the fields with the tag `optional:"true"` or `name:"dependOnName1"`
 func (c *Container) Fill(structure interface{}) error {
+	err := c.fill(&resolution{}, structure)
+	if _, ok := err.(*ResolutionError); ok {
+		return withRequest(err, reflect.TypeOf(structure).Elem(), "")
+	}
+	return err
+}
+
+func (c *Container) fill(r *resolution, structure interface{}) error {
 	// 获取入参类型
 	receiverType := reflect.TypeOf(structure)
 	if receiverType == nil {
-		return errors.New("container: invalid structure")
+		return containerError(ErrInvalidStructure, "structure must not be nil")
 	}
 
 	if receiverType.Kind() == reflect.Ptr {
@@ -381,9 +725,30 @@ func (c *Container) Fill(structure interface{}) error {
 				// 获取第i个字段
 				f := s.Field(i)
 				fType := f.Type()
-				//如果是interface的数组，那么就填充所有实现
+				//指定了value标签的字段填充配置项的值
+				if key, ok := s.Type().Field(i).Tag.Lookup("value"); ok {
+					optional := strings.ToLower(s.Type().Field(i).Tag.Get("optional")) == "true"
+					v, err := c.propertyValue(r, f.Type(), key, optional)
+					if err != nil {
+						return err
+					}
+					ptr := reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+					ptr.Set(v)
+					continue
+				}
+				//指定了group标签的切片填充分组中所有的实例
+				if group, ok := s.Type().Field(i).Tag.Lookup("group"); ok && f.Kind() == reflect.Slice {
+					slice, err := c.groupValue(r, f.Type(), group)
+					if err != nil {
+						return err
+					}
+					ptr := reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+					ptr.Set(slice)
+					continue
+				}
+				//如果是interface的数组或者以名字为key的interface的map，那么就填充所有实现
 				sliceFill := false
-				if f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.Interface {
+				if isInterfaceSlice(f.Type()) || isInterfaceMap(f.Type()) {
 					sliceFill = true
 					fType = f.Type().Elem()
 				} else if f.Kind() != reflect.Interface { //只对interface类型执行Fill逻辑
@@ -395,64 +760,205 @@ func (c *Container) Fill(structure interface{}) error {
 					optional = strings.ToLower(b) == "true"
 				}
 
-				namedBinding, ok := c.bind[fType]
-				if !ok {
-					if optional {
+				if sliceFill {
+					bindings := c.sortedBindings(fType)
+					if len(bindings) == 0 && !optional {
+						return newResolutionError(r.path, f.Type().String(), ErrNotFound)
+					}
+					if f.Kind() == reflect.Map {
+						if len(bindings) == 0 {
+							continue
+						}
+						m, err := c.mapValue(r, f.Type(), bindings)
+						if err != nil {
+							return err
+						}
+						ptr := reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+						ptr.Set(m)
 						continue
 					}
-					return errors.New("container: no concrete found for: " + f.Type().String())
-				}
-				if sliceFill {
-					for _, b := range namedBinding.namedBinding {
-						instance, _ := b.resolve(c)
+					for _, b := range bindings {
+						instance, err := b.resolve(c, r)
+						if err != nil {
+							return err
+						}
 						ptr := reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
 						ptr.Set(reflect.Append(ptr, reflect.ValueOf(instance)))
 					}
 					continue
 				}
-				b := namedBinding.defaultBinding
-				//指定了name字段说明该字段依赖的binding name
-				if name, exist := s.Type().Field(i).Tag.Lookup("name"); exist {
-					if concrete, exist := namedBinding.namedBinding[name]; exist {
-						b = concrete
-					} else {
-						return fmt.Errorf("container: cannot resolve %v field", s.Type().Field(i).Name)
+				//指定了name字段说明该字段依赖的binding name，没有指定name，获得默认binding
+				name := s.Type().Field(i).Tag.Get("name")
+				b, err := c.getBinding(fType, name)
+				if err != nil {
+					if optional {
+						continue
 					}
+					return newResolutionError(r.path, describe(fType, name), err)
+				}
+				instance, err := b.resolve(c, r)
+				if err != nil {
+					return err
 				}
-				//没有指定name，获得默认binding
-				instance, _ := b.resolve(c)
 				ptr := reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
 				ptr.Set(reflect.ValueOf(instance))
 			}
 			return nil
 		}
-		return errors.New("container: invalid structure, input elem type:" + elem.Kind().String())
+		return containerError(ErrInvalidStructure, "input elem type: %s", elem.Kind())
 	}
 
-	return errors.New("container: invalid structure, input type:" + receiverType.Kind().String())
+	return containerError(ErrInvalidStructure, "input type: %s", receiverType.Kind())
 }
 
 // Reset deletes all the existing bindings and empties the container instance.
 func (c *Container) Reset() {
+	c.mu.Lock()
 	for k := range c.bind {
 		delete(c.bind, k)
 	}
 	for k := range c.alias {
 		delete(c.alias, k)
 	}
+	c.overridden = make(map[*binding]*instanceSlot)
+	c.overriddenCalls = make(map[*sharedCall]*sharedCall)
+	c.decorators = make(map[reflect.Type][]*binding)
+	c.groups = make(map[string][]*binding)
+	c.properties = nil
+	c.profiles = nil
+	c.conditional = make(map[reflect.Type][]*binding)
+	c.modules = make(map[string]*Container)
+	c.observers = nil
+	c.mu.Unlock()
+	c.lifeMu.Lock()
+	c.resolved = nil
+	c.started = nil
+	c.nStarted = 0
+	c.lifeMu.Unlock()
 }
 func (c *Container) Clone() *Container {
-	clone := &Container{
-		bind:  make(map[reflect.Type]*namedBinding, len(c.bind)),
-		alias: make(map[reflect.Type]reflect.Type, len(c.alias)),
+	clone := &Container{parent: c.parent}
+	c.copyTo(clone)
+	return clone
+}
+
+// copyTo 将本容器的binding等注册信息复制到target中，复制出的binding属于target，target中原有的注册信息以及实例被丢弃
+func (c *Container) copyTo(target *Container) {
+	c.copyWith(target, &containerCopy{
+		containers: make(map[*Container]*Container),
+		bindings:   make(map[*binding]*binding),
+		calls:      make(map[*sharedCall]*sharedCall),
+	})
+}
+
+// containerCopy 记录复制容器时原来的容器、binding和共享的调用结果与复制出的对象的对应关系
+type containerCopy struct {
+	containers map[*Container]*Container
+	bindings   map[*binding]*binding
+	calls      map[*sharedCall]*sharedCall
+}
+
+// binding 获得b复制出的binding，同一个binding只复制一次，这样接口、分组和导出它的容器中仍然是同一个对象
+func (cp *containerCopy) binding(b *binding, target *Container) *binding {
+	if clone, ok := cp.bindings[b]; ok {
+		return clone
+	}
+	clone := b.Clone()
+	if owner, ok := cp.containers[b.owner]; ok {
+		clone.owner = owner
+	} else {
+		clone.owner = b.resolver(target)
+	}
+	//同一个构造函数注册的binding在复制后仍然共享调用结果
+	if b.call != nil {
+		if _, ok := cp.calls[b.call]; !ok {
+			cp.calls[b.call] = b.call.clone()
+		}
+		clone.call = cp.calls[b.call]
 	}
+	cp.bindings[b] = clone
+	return clone
+}
+
+func (c *Container) copyWith(target *Container, cp *containerCopy) {
+	cp.containers[c] = target
+	//先复制binding的列表再克隆，避免持有容器的锁时等待正在构造的单例
+	c.mu.RLock()
+	bind := make(map[reflect.Type]*namedBinding, len(c.bind))
 	for k, v := range c.bind {
-		clone.bind[k] = v.Clone()
+		bind[k] = v.copy()
 	}
+	alias := make(map[reflect.Type]reflect.Type, len(c.alias))
 	for k, v := range c.alias {
-		clone.alias[k] = v
+		alias[k] = v
 	}
-	return clone
+	decorators := make(map[reflect.Type][]*binding, len(c.decorators))
+	for k, v := range c.decorators {
+		decorators[k] = append([]*binding(nil), v...)
+	}
+	properties := append([]PropertySource(nil), c.properties...)
+	profiles := append([]string(nil), c.profiles...)
+	modules := make(map[string]*Container, len(c.modules))
+	for k, v := range c.modules {
+		modules[k] = v
+	}
+	conditional := make(map[reflect.Type][]*binding, len(c.conditional))
+	for k, v := range c.conditional {
+		conditional[k] = append([]*binding(nil), v...)
+	}
+	groups := make(map[string][]*binding, len(c.groups))
+	for k, v := range c.groups {
+		groups[k] = append([]*binding(nil), v...)
+	}
+	tracker := c.tracker
+	c.mu.RUnlock()
+	if mapped, ok := cp.containers[tracker]; ok {
+		tracker = mapped
+	}
+	//模块的私有容器也要复制，导出的binding在复制出的私有容器中构造，实例由target管理
+	for name, private := range modules {
+		clone := &Container{parent: target}
+		private.copyWith(clone, cp)
+		modules[name] = clone
+	}
+	for k, v := range bind {
+		nb := &namedBinding{namedBinding: make(map[string]*binding, len(v.namedBinding))}
+		for name, b := range v.namedBinding {
+			nb.namedBinding[name] = cp.binding(b, target)
+		}
+		nb.defaultBinding = cp.binding(v.defaultBinding, target)
+		bind[k] = nb
+	}
+	for _, v := range conditional {
+		for i, b := range v {
+			v[i] = cp.binding(b, target)
+		}
+	}
+	//分组中的binding与接口的binding是同一个对象，被覆盖的binding单独克隆
+	for _, v := range groups {
+		for i, b := range v {
+			v[i] = cp.binding(b, target)
+		}
+	}
+	target.mu.Lock()
+	target.bind = bind
+	target.alias = alias
+	target.overridden = make(map[*binding]*instanceSlot)
+	target.overriddenCalls = make(map[*sharedCall]*sharedCall)
+	target.decorators = decorators
+	target.groups = groups
+	target.properties = properties
+	target.profiles = profiles
+	target.conditional = conditional
+	target.modules = modules
+	target.tracker = tracker
+	target.observers = nil
+	target.mu.Unlock()
+	target.lifeMu.Lock()
+	target.resolved = nil
+	target.started = nil
+	target.nStarted = 0
+	target.lifeMu.Unlock()
 }
 
 // container is the global repository of bindings
@@ -463,32 +969,132 @@ func Resolve(abstraction interface{}, options ...ResolveOption) error {
 	return container.Resolve(abstraction, options...)
 }
 
+//ResolveContext 在全局容器中获得接口对应的实例，ctx会注入到构造函数中context.Context类型的参数
+func ResolveContext(ctx context.Context, abstraction interface{}, options ...ResolveOption) error {
+	return container.ResolveContext(ctx, abstraction, options...)
+}
+
 //Register register interface-> constructor
 func Register(constructor interface{}, options ...Option) error {
 	return container.Register(constructor, options...)
 }
 
+//Start 按依赖顺序执行全局容器中实例的OnStart钩子
+func Start(ctx context.Context) error {
+	return container.Start(ctx)
+}
+
+//Stop 按依赖的逆序执行全局容器中实例的OnStop钩子
+func Stop(ctx context.Context) error {
+	return container.Stop(ctx)
+}
+
+//AddPropertySource 为全局容器添加一个配置项来源
+func AddPropertySource(source PropertySource) {
+	container.AddPropertySource(source)
+}
+
+//ActivateProfiles 在全局容器中激活profile
+func ActivateProfiles(profiles ...string) {
+	container.ActivateProfiles(profiles...)
+}
+
+//Install 将模块安装到全局容器中
+func Install(modules ...*Module) error {
+	return container.Install(modules...)
+}
+
+//Decorate 在全局容器中注册一个装饰器
+func Decorate(decorator interface{}, options ...Option) error {
+	return container.Decorate(decorator, options...)
+}
+
+//DependencyGraph 获得全局容器中注册的依赖关系图
+func DependencyGraph() *Graph {
+	return container.DependencyGraph()
+}
+
+//Validate 检查全局容器中所有binding的依赖是否都能被满足
+func Validate() error {
+	return container.Validate()
+}
+
+//CheckCycles 检查全局容器中注册的binding之间是否存在循环依赖
+func CheckCycles() error {
+	return container.CheckCycles()
+}
+
+//NewChild 创建全局容器的一个子容器
+func NewChild() *Container {
+	return container.NewChild()
+}
+
+//NewScope 创建全局容器的一个作用域
+func NewScope() *Scope {
+	return container.NewScope()
+}
+
+//Close 按创建的逆序释放全局容器中的单例实例
+func Close(ctx context.Context) error {
+	return container.Close(ctx)
+}
+
 // Reset deletes all the existing bindings and empties the container instance.
 func Reset() {
 	container.Reset()
 }
 
-//Fill takes a struct and resolves the fields with the tag `optional:"true"` or `name:"dependOnName1"`
+//Override 临时替换全局容器中接口的binding，返回的函数用于恢复
+func Override(interfacePtr interface{}, instance interface{}, options ...Option) (restore func(), err error) {
+	return container.Override(interfacePtr, instance, options...)
+}
+
+//Observe 注册全局容器的观察者，返回的函数用于取消观察
+func Observe(fn func(t reflect.Type, name string)) (cancel func()) {
+	return container.Observe(fn)
+}
+
+//Snapshot 获得全局容器当前状态的快照，之后可以通过Restore恢复
+func Snapshot() *Container {
+	return container.Clone()
+}
+
+//Restore 将全局容器恢复为snapshot的状态，snapshot可以多次用于恢复
+func Restore(snapshot *Container) {
+	snapshot.copyTo(container)
+}
+
+// Fill takes a struct and resolves the fields with the tag `optional:"true"` or `name:"dependOnName1"`
 //argument must be a struct point
 func Fill(structure interface{}) error {
 	return container.Fill(structure)
 }
 
+//FillContext 使用全局容器填充struct，ctx会注入到构造函数中context.Context类型的参数
+func FillContext(ctx context.Context, structure interface{}) error {
+	return container.FillContext(ctx, structure)
+}
+
 //Call invoke function that use interface as parameters
 func Call(function interface{}, options ...CallOption) ([]interface{}, error) {
 	return container.Call(function, options...)
 }
 
+//CallContext 使用全局容器调用函数，ctx会注入到函数以及构造函数中context.Context类型的参数
+func CallContext(ctx context.Context, function interface{}, options ...CallOption) ([]interface{}, error) {
+	return container.CallContext(ctx, function, options...)
+}
+
 //RegisterInstance register interface->instance into container
 func RegisterInstance(interfacePtr interface{}, instance interface{}, options ...Option) error {
 	return container.RegisterInstance(interfacePtr, instance, options...)
 }
 
+//RegisterType 在全局容器中注册一个struct类型，自动生成其构造函数
+func RegisterType(prototype interface{}, options ...Option) error {
+	return container.RegisterType(prototype, options...)
+}
+
 //RegisterSubInterface set sub interface map to another interface
 func RegisterSubInterface(subInterfacePtr interface{}, interfacePtr interface{}) error {
 	return container.RegisterSubInterface(subInterfacePtr, interfacePtr)
@@ -499,6 +1105,9 @@ func SetDefaultBinding(interfacePtr interface{}, defaultName string) error {
 	return container.SetDefaultBinding(interfacePtr, defaultName)
 }
 func isNil(i interface{}) bool {
+	if i == nil {
+		return true
+	}
 	vi := reflect.ValueOf(i)
 	if vi.Kind() == reflect.Ptr {
 		return vi.IsNil()
@@ -0,0 +1,62 @@
+package iocgo
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_NewChild(t *testing.T) {
+	log = ""
+	parent := NewContainer()
+	parent.Register(NewFoobar)
+	parent.Register(func() Fooer { return &Foo{} })
+	parent.Register(func() Barer { return &Bar{} })
+	var fb Foobarer
+	assert.Nil(t, parent.Resolve(&fb))
+
+	child := parent.NewChild()
+	child.Register(func() Barer { return &Baz{} }) //覆盖父容器中的Barer
+	var f1, f2 Fooer
+	assert.Nil(t, parent.Resolve(&f1))
+	assert.Nil(t, child.Resolve(&f2))
+	assert.True(t, f1 == f2) //父容器中的单例是共享的
+
+	var childFb Foobarer
+	assert.Nil(t, child.Resolve(&childFb))
+	assert.False(t, fb == childFb)
+	childFb.Say(123, "Hello World")
+	assert.True(t, strings.Contains(log, "baz:"))
+	assert.True(t, childFb.(*Foobar).foo == f1)
+
+	var fb2 Foobarer
+	assert.Nil(t, parent.Resolve(&fb2))
+	assert.True(t, fb == fb2) //子容器的覆盖不影响父容器
+
+	sibling := parent.NewChild()
+	var siblingFb Foobarer
+	assert.Nil(t, sibling.Resolve(&siblingFb))
+	assert.True(t, fb == siblingFb)
+}
+
+func TestContainer_NewChildNamed(t *testing.T) {
+	closeLog = nil
+	defer Reset()
+	Register(NewFoobar, DependsOn(map[int]string{1: "bar"}))
+	Register(func() Fooer { return &Foo{} })
+	Register(func() Barer { return &Bar{} }, Name("bar"))
+	child := NewChild()
+	child.Register(func() Barer { return &disposableBar{} }, Name("baz"))
+	input := FoobarInputMultiBar{}
+	assert.Nil(t, child.Fill(&input))
+	assert.Equal(t, 2, len(input.bar))
+	var b Barer
+	assert.Nil(t, child.Resolve(&b, ResolveName("bar")))
+	assert.Nil(t, child.Resolve(&b, ResolveName("baz")))
+	var fb Foobarer
+	assert.Nil(t, child.Resolve(&fb))
+	assert.Nil(t, child.Close(context.Background()))
+	assert.Equal(t, []string{"bar"}, closeLog) //子容器只释放自己的实例
+}
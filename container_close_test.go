@@ -0,0 +1,95 @@
+package iocgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var closeLog []string
+
+type closableFoo struct {
+}
+
+func (closableFoo) Foo(i int) {
+	Println("foo:", i)
+}
+func (closableFoo) Foo2(s string) {
+	Println("foo2:", s)
+}
+
+func (closableFoo) Close() error {
+	closeLog = append(closeLog, "foo")
+	return nil
+}
+
+type disposableBar struct {
+	Baz
+	err error
+}
+
+func (b *disposableBar) Dispose(ctx context.Context) error {
+	closeLog = append(closeLog, "bar")
+	return b.err
+}
+
+type closableFoobar struct {
+	Foobar
+}
+
+func (*closableFoobar) Close() error {
+	closeLog = append(closeLog, "foobar")
+	return errors.New("foobar close error")
+}
+
+func TestContainer_Close(t *testing.T) {
+	closeLog = nil
+	c := NewContainer()
+	c.Register(func(f Fooer, b Barer) Foobarer { return &closableFoobar{Foobar{foo: f, bar: b}} })
+	c.Register(func() Fooer { return &closableFoo{} })
+	c.Register(func() Barer { return &disposableBar{err: errors.New("bar dispose error")} })
+	var fb Foobarer
+	err := c.Resolve(&fb)
+	assert.Nil(t, err)
+	err = c.Close(context.Background())
+	assert.NotNil(t, err)
+	t.Log(err)
+	//依赖者先于依赖被释放
+	assert.Equal(t, []string{"foobar", "bar", "foo"}, closeLog)
+	var multi MultiError
+	assert.True(t, errors.As(err, &multi))
+	assert.Equal(t, 2, len(multi))
+	//释放后再次Resolve会构造新的实例
+	var fb2 Foobarer
+	err = c.Resolve(&fb2)
+	assert.Nil(t, err)
+	assert.False(t, fb == fb2)
+}
+
+func TestContainer_CloseExternallyOwned(t *testing.T) {
+	closeLog = nil
+	defer Reset()
+	var f Fooer
+	var b Barer
+	RegisterInstance(&f, &closableFoo{})
+	RegisterInstance(&b, &disposableBar{}, ExternallyOwned())
+	err := Close(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"foo"}, closeLog)
+}
+
+func TestMultiError_IsAs(t *testing.T) {
+	errFoo := errors.New("foo")
+	multi := MultiError{errFoo, &ResolutionError{Err: ErrNotFound}}
+	//直接调用Is和As，不依赖Go 1.20的errors.Is对Unwrap() []error的遍历
+	assert.True(t, multi.Is(errFoo))
+	assert.True(t, multi.Is(ErrNotFound))
+	assert.False(t, multi.Is(ErrNoScope))
+	var resolveErr *ResolutionError
+	assert.True(t, multi.As(&resolveErr))
+	assert.True(t, errors.Is(resolveErr, ErrNotFound))
+	var cycle *CycleError
+	assert.False(t, multi.As(&cycle))
+}
@@ -0,0 +1,67 @@
+package iocgo
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_ConcurrentResolve(t *testing.T) {
+	c := NewContainer()
+	var constructed int32
+	c.Register(func(f Fooer, b Barer) Foobarer {
+		atomic.AddInt32(&constructed, 1)
+		return &Foobar{foo: f, bar: b}
+	})
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} })
+	const n = 50
+	results := make([]Foobarer, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.Nil(t, c.Resolve(&results[i]))
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), constructed)
+	for _, fb := range results {
+		assert.True(t, fb == results[0])
+	}
+}
+
+func TestContainer_ConcurrentRegisterResolveFill(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} })
+	c.Register(NewFoobarWithMsg, Parameters(map[int]interface{}{2: "studyzy"}), Lifestyle(true))
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			c.Register(func() Barer { return &Baz{} }, Name(string(rune('a'+i))))
+		}(i)
+		go func() {
+			defer wg.Done()
+			input := FoobarInputMultiBar{}
+			assert.Nil(t, c.Fill(&input))
+		}()
+		go func(i int) {
+			defer wg.Done()
+			//每次调用的Arguments不会影响其他调用
+			msg := string(rune('a' + i))
+			var fb Foobarer
+			assert.Nil(t, c.Resolve(&fb, Arguments(map[int]interface{}{2: msg})))
+			assert.Equal(t, msg, fb.(*Foobar).msg)
+		}(i)
+	}
+	wg.Wait()
+	var fb Foobarer
+	assert.Nil(t, c.Resolve(&fb))
+	assert.Equal(t, "studyzy", fb.(*Foobar).msg)
+}
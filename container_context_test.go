@@ -0,0 +1,130 @@
+package iocgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey struct{}
+
+func TestContainer_ResolveContext(t *testing.T) {
+	c := NewContainer()
+	c.Register(func(ctx context.Context) Barer {
+		name, _ := ctx.Value(ctxKey{}).(string)
+		return &namedBar{name: name}
+	}, Lifestyle(true))
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	var bar Barer
+	assert.Nil(t, c.ResolveContext(ctx, &bar))
+	assert.Equal(t, "request", bar.(*namedBar).name)
+
+	//没有传入ctx时注入context.Background()
+	assert.Nil(t, c.Resolve(&bar))
+	assert.Equal(t, "", bar.(*namedBar).name)
+	assert.Nil(t, c.Validate())
+
+	results, err := c.CallContext(ctx, func(ctx context.Context, bar Barer) string {
+		return ctx.Value(ctxKey{}).(string) + " " + bar.(*namedBar).name
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "request request", results[0])
+
+	type holder struct {
+		Bar Barer
+	}
+	h := &holder{}
+	assert.Nil(t, c.FillContext(ctx, h))
+	assert.Equal(t, "request", h.Bar.(*namedBar).name)
+
+	scope := c.NewScope()
+	defer scope.Close(ctx)
+	assert.Nil(t, scope.ResolveContext(ctx, &bar))
+	assert.Equal(t, "request", bar.(*namedBar).name)
+}
+
+func TestContainer_ResolveContextCancelled(t *testing.T) {
+	defer Reset()
+	called := false
+	Register(func(ctx context.Context) Fooer {
+		called = true
+		return &Foo{}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var foo Fooer
+	err := ResolveContext(ctx, &foo)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, called)
+	assert.Nil(t, foo)
+
+	_, err = CallContext(ctx, func(f Fooer) { called = true })
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, called)
+
+	type holder struct {
+		Foo Fooer
+	}
+	h := &holder{}
+	err = FillContext(ctx, h)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Nil(t, h.Foo)
+}
+
+func TestContainer_ResolveContextDeadline(t *testing.T) {
+	c := NewContainer()
+	release := make(chan struct{})
+	defer close(release)
+	c.Register(func(ctx context.Context) Fooer {
+		<-release //构造函数忽略了ctx
+		return &Foo{}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var foo Fooer
+	err := c.ResolveContext(ctx, &foo)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	var resolveErr *ResolutionError
+	assert.True(t, errors.As(err, &resolveErr))
+	assert.Nil(t, foo)
+
+	var bar Barer
+	c.RegisterInstance((*Fooer)(nil), &Foo{}, Name("instance"))
+	c.Register(func(ctx context.Context, f Fooer) (Barer, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, DependsOn(map[int]string{1: "instance"}), Name("waiting"), Lifestyle(true))
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = c.ResolveContext(ctx, &bar, ResolveName("waiting"))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+type ctxHolder struct {
+	ctx context.Context
+}
+
+func (ctxHolder) Bar(s string) {}
+
+func TestContainer_ResolveContextSingleton(t *testing.T) {
+	c := NewContainer()
+	c.Register(func(ctx context.Context) Barer { return ctxHolder{ctx} }, Name("singleton"))
+	c.Register(func(ctx context.Context) Barer { return ctxHolder{ctx} }, Name("transient"), Lifestyle(true))
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "request"))
+	var singleton, transient Barer
+	assert.Nil(t, c.ResolveContext(ctx, &singleton, ResolveName("singleton")))
+	assert.Nil(t, c.ResolveContext(ctx, &transient, ResolveName("transient")))
+	cancel()
+
+	//单例得到的ctx保留调用者ctx中的值，但是不会随之取消
+	singletonCtx := singleton.(ctxHolder).ctx
+	assert.Equal(t, "request", singletonCtx.Value(ctxKey{}))
+	assert.Nil(t, singletonCtx.Err())
+	assert.Nil(t, singletonCtx.Done())
+	_, hasDeadline := singletonCtx.Deadline()
+	assert.False(t, hasDeadline)
+	assert.True(t, errors.Is(transient.(ctxHolder).ctx.Err(), context.Canceled))
+}
@@ -0,0 +1,49 @@
+package iocgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_ResolveCycle(t *testing.T) {
+	c := NewContainer()
+	c.Register(NewFoobar, DependsOn(map[int]string{0: "x"}))
+	c.Register(func(fb Foobarer) Fooer { return &Foo{} }, Name("x"))
+	c.Register(func() Barer { return &Bar{} })
+	var fb Foobarer
+	err := c.Resolve(&fb)
+	assert.NotNil(t, err)
+	t.Log(err)
+	assert.True(t, errors.Is(err, ErrCircularDependency))
+	var cycle *CycleError
+	assert.True(t, errors.As(err, &cycle))
+	assert.Equal(t, "cycle: iocgo.Foobarer -> iocgo.Fooer(name=x) -> iocgo.Foobarer", cycle.Error())
+}
+
+func TestContainer_ResolveSelfCycle(t *testing.T) {
+	defer Reset()
+	Register(func(b Barer) Barer { return b }, Lifestyle(true))
+	var b Barer
+	err := Resolve(&b)
+	var cycle *CycleError
+	assert.True(t, errors.As(err, &cycle))
+	assert.Equal(t, "cycle: iocgo.Barer -> iocgo.Barer", cycle.Error())
+}
+
+func TestContainer_CheckCycles(t *testing.T) {
+	defer Reset()
+	Register(NewFoobar)
+	Register(func() Fooer { return &Foo{} })
+	Register(func() Barer { return &Bar{} })
+	assert.Nil(t, CheckCycles())
+
+	Register(func(fb Foobarer) Barer { return &Bar{} }, Name("bar"), Default())
+	err := CheckCycles()
+	assert.NotNil(t, err)
+	t.Log(err)
+	var cycle *CycleError
+	assert.True(t, errors.As(err, &cycle))
+	assert.Equal(t, []string{"iocgo.Barer(name=bar)", "iocgo.Foobarer", "iocgo.Barer(name=bar)"}, cycle.Path)
+}
@@ -0,0 +1,121 @@
+package iocgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingBar struct {
+	inner Barer
+	count *int
+}
+
+func (b *countingBar) Bar(s string) {
+	*b.count++
+	b.inner.Bar(s)
+}
+
+type prefixBar struct {
+	inner  Barer
+	prefix string
+}
+
+func (b *prefixBar) Bar(s string) {
+	b.inner.Bar(b.prefix + s)
+}
+
+func TestContainer_Decorate(t *testing.T) {
+	log = ""
+	c := NewContainer()
+	count := 0
+	c.Register(func() Barer { return &Bar{} })
+	c.RegisterInstance((*string)(nil), "decorated:")
+	assert.Nil(t, c.Decorate(func(inner Barer, prefix string) Barer { return &prefixBar{inner: inner, prefix: prefix} }))
+	assert.Nil(t, c.Decorate(func(inner Barer) (Barer, error) { return &countingBar{inner: inner, count: &count}, nil }))
+	var b Barer
+	assert.Nil(t, c.Resolve(&b))
+	b.Bar("hello")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "bar: decorated:hello\n", log)
+	//装饰器按注册顺序叠加，后注册的在最外层
+	counting, ok := b.(*countingBar)
+	assert.True(t, ok)
+	_, ok = counting.inner.(*prefixBar)
+	assert.True(t, ok)
+
+	var b2 Barer
+	assert.Nil(t, c.Resolve(&b2))
+	assert.True(t, b == b2)
+}
+
+func TestContainer_DecorateName(t *testing.T) {
+	defer Reset()
+	RegisterInstance((*Barer)(nil), &Bar{}, Name("a"))
+	RegisterInstance((*Barer)(nil), &Bar{}, Name("b"))
+	Decorate(func(inner Barer) Barer { return &prefixBar{inner: inner, prefix: "a:"} }, Name("a"))
+	var a, b Barer
+	assert.Nil(t, Resolve(&a, ResolveName("a")))
+	assert.Nil(t, Resolve(&b, ResolveName("b")))
+	_, ok := a.(*prefixBar)
+	assert.True(t, ok)
+	_, ok = b.(*Bar)
+	assert.True(t, ok)
+}
+
+func TestContainer_DecorateLifecycle(t *testing.T) {
+	closeLog = nil
+	c := NewContainer()
+	c.Register(func() Fooer { return &closableFoo{} }, OnStart(func(ctx context.Context, instance interface{}) error {
+		_, ok := instance.(*closableFoo)
+		assert.True(t, ok, "hooks receive the original instance")
+		return nil
+	}))
+	c.Decorate(func(inner Fooer) Fooer { return &Foo{} })
+	assert.Nil(t, c.Start(context.Background()))
+	var f Fooer
+	assert.Nil(t, c.Resolve(&f))
+	_, ok := f.(*Foo)
+	assert.True(t, ok)
+	assert.Nil(t, c.Close(context.Background()))
+	assert.Equal(t, []string{"foo"}, closeLog)
+}
+
+func TestContainer_DecorateError(t *testing.T) {
+	c := NewContainer()
+	assert.True(t, errors.Is(c.Decorate(nil), ErrInvalidDecorator))
+	assert.True(t, errors.Is(c.Decorate(func() Barer { return nil }), ErrInvalidDecorator))
+	assert.True(t, errors.Is(c.Decorate(func(b Barer) Fooer { return nil }), ErrInvalidDecorator))
+
+	c.Register(func() Barer { return &Bar{} })
+	c.Decorate(func(inner Barer, f Fooer) Barer { return inner })
+	err := c.Validate()
+	assert.True(t, errors.Is(err, ErrNotFound))
+	var b Barer
+	err = c.Resolve(&b)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	c.Register(func() Fooer { return &Foo{} })
+	c.Decorate(func(inner Barer) (Barer, error) { return nil, errors.New("decorate failed") })
+	err = c.Resolve(&b)
+	assert.NotNil(t, err)
+	assert.Equal(t, "decorate failed", errors.Unwrap(err).Error())
+}
+
+func TestContainer_DecorateChild(t *testing.T) {
+	parent := NewContainer()
+	parent.Register(func() Barer { return &Bar{} }, Lifestyle(true))
+	parent.Decorate(func(inner Barer) Barer { return &prefixBar{inner: inner, prefix: "parent:"} })
+	child := parent.NewChild()
+	child.Decorate(func(inner Barer) Barer { return &prefixBar{inner: inner, prefix: "child:"} })
+	var b Barer
+	assert.Nil(t, child.Resolve(&b))
+	assert.Equal(t, "parent:", b.(*prefixBar).prefix)
+
+	child.Register(func() Barer { return &Bar{} }, Lifestyle(true))
+	assert.Nil(t, child.Resolve(&b))
+	assert.Equal(t, "child:", b.(*prefixBar).prefix)
+	assert.Equal(t, "parent:", b.(*prefixBar).inner.(*prefixBar).prefix)
+}
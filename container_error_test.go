@@ -89,9 +89,64 @@ func TestContainer_ResolveReturnError(t *testing.T) {
 	var fb Foobarer
 	err := Resolve(&fb)
 	assert.NotNil(t, err)
-	assert.Equal(t, "input nil", err.Error())
+	assert.Equal(t, "input nil", errors.Unwrap(err).Error())
 	t.Log(err)
 	Register(func() Fooer { return &Foo{} })
 	err = Resolve(&fb)
 	assert.Nil(t, err)
 }
+
+func TestContainer_ErrorsIs(t *testing.T) {
+	c := NewContainer()
+	assert.True(t, errors.Is(c.Register(&Foo{}), ErrInvalidConstructor))
+	var f Fooer
+	assert.True(t, errors.Is(c.Register(NewBar, Interface(&f)), ErrInvalidAbstraction))
+	assert.True(t, errors.Is(c.RegisterInstance(f, &Foo{}), ErrInvalidAbstraction))
+	assert.True(t, errors.Is(c.SetDefaultBinding(&f, "foo"), ErrNotFound))
+	assert.True(t, errors.Is(c.Resolve(f), ErrInvalidAbstraction))
+	_, err := c.Call(&Foo{})
+	assert.True(t, errors.Is(err, ErrInvalidFunction))
+	assert.True(t, errors.Is(c.Fill(FoobarInput{}), ErrInvalidStructure))
+
+	var resolutionErr *ResolutionError
+	err = c.Resolve(&f, ResolveName("foo"))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.As(err, &resolutionErr))
+	assert.Equal(t, "iocgo.Fooer", resolutionErr.Type.String())
+	assert.Equal(t, "foo", resolutionErr.Name)
+}
+
+func TestContainer_ResolutionErrorPath(t *testing.T) {
+	c := NewContainer()
+	c.Register(NewFoobar, DependsOn(map[int]string{1: "bar"}))
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} })
+	var fb Foobarer
+	err := c.Resolve(&fb)
+	t.Log(err)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	var resolutionErr *ResolutionError
+	assert.True(t, errors.As(err, &resolutionErr))
+	assert.Equal(t, []string{"iocgo.Foobarer", "iocgo.Barer(name=bar)"}, resolutionErr.Path)
+
+	_, err = c.Call(SayHi1, CallDependsOn(map[int]string{1: "bar"}))
+	assert.True(t, errors.As(err, &resolutionErr))
+	assert.Equal(t, "func(iocgo.Fooer, iocgo.Barer)", resolutionErr.Type.String())
+
+	input := FoobarInputWithTag{}
+	err = c.Fill(&input)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.As(err, &resolutionErr))
+	assert.Equal(t, []string{"iocgo.Barer(name=baz)"}, resolutionErr.Path)
+}
+
+func TestContainer_ConstructorErrorAs(t *testing.T) {
+	c := NewContainer()
+	c.Register(NewFoobarError, Optional(0))
+	c.Register(func() Barer { return &Bar{} })
+	var fb Foobarer
+	err := c.Resolve(&fb)
+	var resolutionErr *ResolutionError
+	assert.True(t, errors.As(err, &resolutionErr))
+	assert.Equal(t, "input nil", resolutionErr.Err.Error())
+}
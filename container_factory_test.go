@@ -0,0 +1,128 @@
+package iocgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type foobarFactory struct {
+	create func(msg string) Foobarer
+}
+
+func TestContainer_Factory(t *testing.T) {
+	log = ""
+	c := NewContainer()
+	c.Register(NewFoobarWithMsg)
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} })
+	c.Register(func(create func(msg string) Foobarer) *foobarFactory {
+		return &foobarFactory{create: create}
+	})
+	var factory *foobarFactory
+	assert.Nil(t, c.Resolve(&factory))
+	fb1 := factory.create("hello")
+	fb2 := factory.create("world")
+	assert.False(t, fb1 == fb2)
+	assert.Equal(t, "hello", fb1.(*Foobar).msg)
+	assert.Equal(t, "world", fb2.(*Foobar).msg)
+
+	//其余的参数仍然从容器中获得，单例是共享的
+	var b Barer
+	assert.Nil(t, c.Resolve(&b))
+	assert.True(t, b == fb1.(*Foobar).bar)
+	assert.True(t, b == fb2.(*Foobar).bar)
+}
+
+func TestContainer_FactoryWithError(t *testing.T) {
+	defer Reset()
+	Register(NewFoobarWithMsg, Name("msg"))
+	Register(func() Barer { return &Bar{} })
+	var create func(Barer, string) (Foobarer, error)
+	_, err := Call(func(f func(Barer, string) (Foobarer, error)) { create = f }, CallDependsOn(map[int]string{0: "msg"}))
+	assert.Nil(t, err)
+
+	//Fooer没有注册
+	_, err = create(&Bar{}, "hello")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	var re *ResolutionError
+	assert.True(t, errors.As(err, &re))
+	assert.Equal(t, "iocgo.Foobarer", re.Type.String())
+	assert.Equal(t, "msg", re.Name)
+
+	Register(func() Fooer { return &Foo{} })
+	b := &Bar{}
+	fb, err := create(b, "hello")
+	assert.Nil(t, err)
+	assert.True(t, b == fb.(*Foobar).bar)
+	assert.Equal(t, "hello", fb.(*Foobar).msg)
+}
+
+func TestContainer_FactoryMismatch(t *testing.T) {
+	c := NewContainer()
+	c.Register(NewFoobar)
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} })
+	var create func(int) (Foobarer, error)
+	_, err := c.Call(func(f func(int) (Foobarer, error)) { create = f })
+	assert.Nil(t, err)
+	_, err = create(1)
+	assert.True(t, errors.Is(err, ErrInvalidFunction))
+
+	c.RegisterInstance(&Foobar{msg: "instance"}, Interface((*Foobarer)(nil)), Name("instance"))
+	var create2 func(string) Foobarer
+	_, err = c.Call(func(f func(string) Foobarer) { create2 = f }, CallDependsOn(map[int]string{0: "instance"}))
+	assert.Nil(t, err)
+	assert.Panics(t, func() { create2("x") })
+}
+
+func TestContainer_ValidateFactory(t *testing.T) {
+	c := NewContainer()
+	c.Register(NewFoobarWithMsg)
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} })
+	c.Register(func(create func(msg string) Foobarer) *foobarFactory {
+		return &foobarFactory{create: create}
+	})
+	//msg由工厂调用时传入
+	assert.Nil(t, c.Validate())
+
+	//不传入msg的工厂或者直接依赖Foobarer时，msg仍然需要从容器获得
+	child := c.NewChild()
+	child.Register(func(create func() Foobarer) Barer { return &Bar{} }, Name("factory"))
+	assert.True(t, errors.Is(child.Validate(), ErrNotFound))
+	child = c.NewChild()
+	child.Register(func(fb Foobarer) Barer { return &Bar{} }, Name("direct"))
+	assert.True(t, errors.Is(child.Validate(), ErrNotFound))
+}
+
+func TestContainer_ValidateFactoryWithoutError(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} })
+	c.Register(func(f Fooer, b Barer, msg string) (Foobarer, error) {
+		if msg == "" {
+			return nil, errors.New("empty msg")
+		}
+		return NewFoobarWithMsg(f, b, msg), nil
+	})
+	c.Register(func(create func(msg string) Foobarer) *foobarFactory {
+		return &foobarFactory{create: create}
+	})
+	err := c.Validate()
+	assert.True(t, errors.Is(err, ErrInvalidFunction))
+	assert.False(t, errors.Is(err, ErrNotFound))
+	var factory *foobarFactory
+	assert.Nil(t, c.Resolve(&factory))
+	assert.Panics(t, func() { factory.create("") })
+
+	//工厂返回error时可以得到构造函数的错误
+	c = NewContainer()
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() (Barer, error) { return &Bar{}, nil })
+	c.Register(func(create func() (Barer, error)) *barRouter { return &barRouter{} })
+	assert.Nil(t, c.Validate())
+	c.Register(func(create func() Barer) *barRouter { return &barRouter{} }, Name("provider"))
+	assert.True(t, errors.Is(c.Validate(), ErrInvalidFunction))
+}
@@ -0,0 +1,41 @@
+package iocgo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Generic(t *testing.T) {
+	log = ""
+	c := NewContainer()
+	assert.Nil(t, c.Register(NewFoobar))
+	assert.Nil(t, Provide[Fooer](c, NewFoo))
+	assert.Nil(t, RegisterInstanceOf[Barer](c, &Bar{}))
+	fb, err := ResolveAs[Foobarer](c)
+	assert.Nil(t, err)
+	fb.Say(123, "Hello World")
+	assert.True(t, strings.Contains(log, "foo:"))
+	assert.True(t, strings.Contains(log, "bar:"))
+	assert.True(t, fb == MustResolve[Foobarer](c))
+
+	_, err = ResolveAs[Foobarer2](c)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Panics(t, func() { MustResolve[Foobarer2](c) })
+	assert.True(t, errors.Is(Provide[Fooer](c, NewBar), ErrInvalidAbstraction))
+}
+
+func TestContainer_GenericDefault(t *testing.T) {
+	defer Reset()
+	assert.Nil(t, Provide[Barer](nil, NewBar, Name("bar")))
+	assert.Nil(t, RegisterInstanceOf[string](nil, "studyzy"))
+	b, err := ResolveAs[Barer](nil, ResolveName("bar"))
+	assert.Nil(t, err)
+	assert.NotNil(t, b)
+	scope := NewScope()
+	s, err := ResolveAs[string](scope)
+	assert.Nil(t, err)
+	assert.Equal(t, "studyzy", s)
+}
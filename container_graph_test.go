@@ -0,0 +1,55 @@
+package iocgo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_DependencyGraph(t *testing.T) {
+	defer Reset()
+	Register(NewFoobar, DependsOn(map[int]string{1: "baz"}))
+	Register(NewSubFoobar, Name("sub"), Lifestyle(true), Optional(1))
+	Register(func() Fooer { return &Foo{} }, Scoped())
+	Register(func() Barer { return &Bar{} }, Name("bar"))
+	var b Barer
+	RegisterInstance(&b, &Baz{}, Name("baz"))
+	g := DependencyGraph()
+	assert.Equal(t, 5, len(g.Nodes))
+	assert.Equal(t, GraphNode{ID: "iocgo.Barer(name=bar)", Type: "iocgo.Barer", Name: "bar", Default: true, Lifestyle: "singleton"}, g.Nodes[0])
+	assert.Equal(t, GraphNode{ID: "iocgo.Barer(name=baz)", Type: "iocgo.Barer", Name: "baz", Lifestyle: "singleton", Instance: true}, g.Nodes[1])
+	assert.Equal(t, []GraphEdge{
+		{From: "iocgo.Foobarer", To: "iocgo.Fooer", Index: 0},
+		{From: "iocgo.Foobarer", To: "iocgo.Barer(name=baz)", Index: 1, Name: "baz"},
+		{From: "iocgo.Foobarer(name=sub)", To: "iocgo.SubFooer", Index: 0, Missing: true},
+		{From: "iocgo.Foobarer(name=sub)", To: "iocgo.Barer(name=bar)", Index: 1, Optional: true},
+	}, g.Edges)
+
+	var sub SubFooer
+	var foo Fooer
+	RegisterSubInterface(&sub, &foo)
+	g = DependencyGraph()
+	assert.Equal(t, []GraphAlias{{From: "iocgo.SubFooer", To: "iocgo.Fooer"}}, g.Aliases)
+	assert.Equal(t, "iocgo.Fooer", g.Edges[2].To)
+
+	data, err := g.JSON()
+	assert.Nil(t, err)
+	t.Log(string(data))
+	var decoded Graph
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, *g, decoded)
+
+	dot := g.DOT()
+	t.Log(dot)
+	assert.True(t, strings.HasPrefix(dot, "digraph iocgo {"))
+	assert.True(t, strings.Contains(dot, `"iocgo.Foobarer" -> "iocgo.Barer(name=baz)" [label="1"];`))
+	assert.True(t, strings.Contains(dot, `"iocgo.SubFooer" -> "iocgo.Fooer" [style=dashed, label="alias"];`))
+
+	mermaid := g.Mermaid()
+	t.Log(mermaid)
+	assert.True(t, strings.HasPrefix(mermaid, "graph LR\n"))
+	assert.True(t, strings.Contains(mermaid, `n0["iocgo.Barer(name=bar)<br/>singleton, default"]`))
+	assert.True(t, strings.Contains(mermaid, "n3 -.->|1| n0"))
+}
@@ -0,0 +1,115 @@
+package iocgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namedBar struct {
+	name string
+}
+
+func (b *namedBar) Bar(s string) {
+	Println(b.name+":", s)
+}
+
+type barCollector struct {
+	bars []Barer
+}
+
+func TestContainer_Group(t *testing.T) {
+	c := NewContainer()
+	//同名的binding会被覆盖，但仍然保留在分组中
+	c.Register(func() Barer { return &namedBar{name: "a"} }, Group("handlers"))
+	c.Register(func() Barer { return &namedBar{name: "b"} }, Group("handlers", "others"))
+	c.RegisterInstance((*Barer)(nil), &namedBar{name: "c"}, Name("c"), Group("handlers"))
+	c.Register(func() Barer { return &namedBar{name: "d"} }, Name("d"))
+	c.Register(func() Fooer { return &Foo{} }, Group("handlers")) //类型不匹配的不会被注入
+	c.Register(func(bars []Barer) *barCollector { return &barCollector{bars: bars} }, DependsOnGroup(map[int]string{0: "handlers"}))
+
+	var collector *barCollector
+	assert.Nil(t, c.Resolve(&collector))
+	names := []string{}
+	for _, b := range collector.bars {
+		names = append(names, b.(*namedBar).name)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+
+	var b Barer
+	assert.Nil(t, c.Resolve(&b))
+	assert.True(t, b == collector.bars[0])
+
+	_, err := c.Call(func(bars []Barer, empty []Barer) {
+		assert.Equal(t, 1, len(bars))
+		assert.NotNil(t, empty)
+		assert.Equal(t, 0, len(empty))
+	}, CallDependsOnGroup(map[int]string{0: "others", 1: "empty"}))
+	assert.Nil(t, err)
+}
+
+func TestContainer_SliceParameter(t *testing.T) {
+	defer Reset()
+	Register(func() Barer { return &namedBar{name: "b"} }, Name("b"))
+	Register(func() Barer { return &namedBar{name: "a"} }, Name("a"))
+	_, err := Call(func(bars []Barer) {
+		assert.Equal(t, 2, len(bars))
+		assert.Equal(t, "b", bars[0].(*namedBar).name)
+		assert.Equal(t, "a", bars[1].(*namedBar).name)
+	})
+	assert.Nil(t, err)
+
+	_, err = Call(func(fooers []Fooer) {})
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestContainer_FillGroup(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Barer { return &namedBar{name: "a"} }, Group("handlers"))
+	c.Register(func() Barer { return &namedBar{name: "b"} }, Name("b"), Group("handlers"))
+	type handlers struct {
+		Bars  []Barer `group:"handlers"`
+		Empty []Barer `group:"empty"`
+	}
+	h := &handlers{}
+	assert.Nil(t, c.Fill(h))
+	assert.Equal(t, 2, len(h.Bars))
+	assert.Equal(t, 0, len(h.Empty))
+	assert.Nil(t, c.Validate())
+}
+
+func TestContainer_GroupChild(t *testing.T) {
+	parent := NewContainer()
+	parent.Register(func() Barer { return &namedBar{name: "parent"} }, Group("handlers"))
+	child := parent.NewChild()
+	child.Register(func() Barer { return &namedBar{name: "child"} }, Name("child"), Group("handlers"))
+	_, err := child.Call(func(bars []Barer) {
+		assert.Equal(t, 2, len(bars))
+		assert.Equal(t, "parent", bars[0].(*namedBar).name)
+		assert.Equal(t, "child", bars[1].(*namedBar).name)
+	}, CallDependsOnGroup(map[int]string{0: "handlers"}))
+	assert.Nil(t, err)
+	_, err = parent.Call(func(bars []Barer) {
+		assert.Equal(t, 1, len(bars))
+	}, CallDependsOnGroup(map[int]string{0: "handlers"}))
+	assert.Nil(t, err)
+
+	clone := parent.Clone()
+	_, err = clone.Call(func(bars []Barer) {
+		assert.Equal(t, 1, len(bars))
+	}, CallDependsOnGroup(map[int]string{0: "handlers"}))
+	assert.Nil(t, err)
+}
+
+func TestContainer_GroupGraph(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Barer { return &namedBar{name: "a"} }, Name("a"), Group("handlers"))
+	c.Register(func() Barer { return &namedBar{name: "b"} }, Name("b"), Group("handlers"))
+	c.Register(func(bars []Barer) Barer { return bars[0] }, Group("handlers"), DependsOnGroup(map[int]string{0: "handlers"}))
+	g := c.DependencyGraph()
+	assert.Equal(t, 3, len(g.Edges))
+	assert.Equal(t, "handlers", g.Edges[0].Group)
+	err := c.CheckCycles()
+	assert.True(t, errors.Is(err, ErrCircularDependency))
+}
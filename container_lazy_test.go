@@ -0,0 +1,103 @@
+package iocgo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lazyFoobar struct {
+	foo Fooer
+	bar Lazy[Barer]
+}
+
+func (f *lazyFoobar) Say(i int, s string) {
+	f.foo.Foo(i)
+	f.bar.MustGet().Bar(s)
+}
+
+func TestContainer_Lazy(t *testing.T) {
+	log = ""
+	c := NewContainer()
+	created := 0
+	c.Register(func(f Fooer, b Lazy[Barer]) Foobarer { return &lazyFoobar{foo: f, bar: b} })
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() Barer {
+		created++
+		return &Bar{}
+	})
+	var fb Foobarer
+	assert.Nil(t, c.Resolve(&fb))
+	assert.Equal(t, 0, created)
+	fb.Say(123, "Hello World")
+	assert.Equal(t, 1, created)
+	assert.True(t, strings.Contains(log, "bar:"))
+	fb.Say(456, "Hello again")
+	assert.Equal(t, 1, created)
+
+	var b Barer
+	assert.Nil(t, c.Resolve(&b))
+	assert.True(t, b == fb.(*lazyFoobar).bar.MustGet())
+}
+
+func TestContainer_LazyProvider(t *testing.T) {
+	defer Reset()
+	var provider func() (Barer, error)
+	Register(func(p func() (Barer, error)) Fooer {
+		provider = p
+		return &Foo{}
+	})
+	var f Fooer
+	assert.Nil(t, Resolve(&f))
+	_, err := provider()
+	assert.True(t, errors.Is(err, ErrNotFound))
+	var re *ResolutionError
+	assert.True(t, errors.As(err, &re))
+	assert.Equal(t, "iocgo.Barer", re.Type.String())
+
+	Register(func() Barer { return &Bar{} })
+	b, err := provider()
+	assert.Nil(t, err)
+	assert.NotNil(t, b)
+}
+
+func TestContainer_LazyNotInjected(t *testing.T) {
+	var l Lazy[Barer]
+	_, err := l.Get()
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Panics(t, func() { l.MustGet() })
+}
+
+func TestContainer_LazyBreakCycle(t *testing.T) {
+	c := NewContainer()
+	c.Register(func(f Fooer, b Barer) Foobarer { return &Foobar{foo: f, bar: b} })
+	c.Register(func(fb Lazy[Foobarer]) Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} })
+	assert.Nil(t, c.CheckCycles())
+	assert.Nil(t, c.Validate())
+	var fb Foobarer
+	assert.Nil(t, c.Resolve(&fb))
+
+	//在构造过程中获取延迟依赖仍然会检测到循环依赖
+	c = NewContainer()
+	c.Register(func(f Fooer, b Barer) Foobarer { return &Foobar{foo: f, bar: b} })
+	c.Register(func(fb Lazy[Foobarer]) Fooer {
+		_, err := fb.Get()
+		assert.True(t, errors.Is(err, ErrCircularDependency))
+		return &Foo{}
+	})
+	c.Register(func() Barer { return &Bar{} })
+	assert.Nil(t, c.Resolve(&fb))
+}
+
+func TestContainer_LazyGraph(t *testing.T) {
+	c := NewContainer()
+	c.Register(func(b Lazy[Barer]) Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} })
+	g := c.DependencyGraph()
+	assert.Equal(t, 1, len(g.Edges))
+	assert.True(t, g.Edges[0].Lazy)
+	assert.Equal(t, "iocgo.Barer", g.Edges[0].To)
+}
@@ -0,0 +1,106 @@
+package iocgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func recordHook(log *[]string, msg string) Hook {
+	return func(ctx context.Context, instance interface{}) error {
+		*log = append(*log, msg)
+		return nil
+	}
+}
+
+func TestContainer_StartStop(t *testing.T) {
+	var hookLog []string
+	c := NewContainer()
+	c.Register(NewFoobar, OnStart(recordHook(&hookLog, "start foobar")), OnStop(recordHook(&hookLog, "stop foobar")))
+	c.Register(func() Fooer { return &Foo{} }, OnStart(recordHook(&hookLog, "start foo")), OnStop(recordHook(&hookLog, "stop foo")))
+	c.Register(func() Barer { return &Bar{} }, OnStart(recordHook(&hookLog, "start bar")), OnStop(recordHook(&hookLog, "stop bar")))
+	err := c.Start(context.Background())
+	assert.Nil(t, err)
+	//Fooer和Barer之间没有依赖关系，只保证它们都先于Foobarer启动
+	assert.Equal(t, 3, len(hookLog))
+	assert.Equal(t, "start foobar", hookLog[2])
+	err = c.Stop(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(hookLog))
+	assert.Equal(t, "stop foobar", hookLog[3])
+	t.Log(hookLog)
+}
+
+func TestContainer_StartRollback(t *testing.T) {
+	var hookLog []string
+	defer Reset()
+	Register(NewFoobar, OnStart(func(ctx context.Context, instance interface{}) error {
+		return errors.New("start foobar error")
+	}))
+	Register(func() Fooer { return &Foo{} }, OnStop(recordHook(&hookLog, "stop foo")))
+	Register(func() Barer { return &Bar{} }, OnStart(recordHook(&hookLog, "start bar")), OnStop(recordHook(&hookLog, "stop bar")))
+	var fb Foobarer
+	err := Resolve(&fb)
+	assert.Nil(t, err)
+	err = Start(context.Background())
+	assert.NotNil(t, err)
+	t.Log(err)
+	assert.Equal(t, []string{"start bar", "stop bar", "stop foo"}, hookLog)
+	err = Stop(context.Background())
+	assert.Nil(t, err)
+}
+
+func TestContainer_StartRetry(t *testing.T) {
+	var hookLog []string
+	c := NewContainer()
+	attempts := 0
+	c.Register(func() Fooer { return &Foo{} }, OnStart(recordHook(&hookLog, "start foo")), OnStop(recordHook(&hookLog, "stop foo")))
+	c.Register(NewFoobar, OnStart(func(ctx context.Context, instance interface{}) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("database is not ready")
+		}
+		hookLog = append(hookLog, "start foobar")
+		return nil
+	}))
+	c.Register(func() Barer { return &Bar{} })
+	var fb Foobarer
+	assert.Nil(t, c.Resolve(&fb))
+	assert.NotNil(t, c.Start(context.Background()))
+	assert.Equal(t, []string{"start foo", "stop foo"}, hookLog)
+
+	//失败回滚之后再次Start会重新启动所有实例
+	hookLog = nil
+	assert.Nil(t, c.Start(context.Background()))
+	assert.Equal(t, []string{"start foo", "start foobar"}, hookLog)
+	assert.Equal(t, 2, attempts)
+	assert.Nil(t, c.Stop(context.Background()))
+	assert.Equal(t, []string{"start foo", "start foobar", "stop foo"}, hookLog)
+}
+
+func TestContainer_StartDeadline(t *testing.T) {
+	c := NewContainer()
+	var started bool
+	c.Register(func() Fooer { return &Foo{} }, OnStart(func(ctx context.Context, instance interface{}) error {
+		started = true
+		return nil
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	err := c.Start(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, started)
+}
+
+func TestContainer_NilHook(t *testing.T) {
+	c := NewContainer()
+	err := c.Register(func() Fooer { return &Foo{} }, OnStart(nil))
+	assert.True(t, errors.Is(err, ErrInvalidFunction))
+	err = c.Register(func() Fooer { return &Foo{} }, OnStop(nil))
+	assert.True(t, errors.Is(err, ErrInvalidFunction))
+	assert.Contains(t, err.Error(), "container:")
+}
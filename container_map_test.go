@@ -0,0 +1,68 @@
+package iocgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type barRouter struct {
+	bars map[string]Barer
+}
+
+func TestContainer_MapParameter(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Barer { return &namedBar{name: "default"} })
+	c.Register(func() Barer { return &namedBar{name: "a"} }, Name("a"))
+	c.RegisterInstance((*Barer)(nil), &namedBar{name: "b"}, Name("b"))
+	c.Register(func(bars map[string]Barer) *barRouter { return &barRouter{bars: bars} })
+	var router *barRouter
+	assert.Nil(t, c.Resolve(&router))
+	assert.Equal(t, 3, len(router.bars))
+	assert.Equal(t, "default", router.bars[""].(*namedBar).name)
+	assert.Equal(t, "a", router.bars["a"].(*namedBar).name)
+	assert.Equal(t, "b", router.bars["b"].(*namedBar).name)
+
+	var a Barer
+	assert.Nil(t, c.Resolve(&a, ResolveName("a")))
+	assert.True(t, a == router.bars["a"])
+	assert.Nil(t, c.Validate())
+
+	//注册了map类型的binding时使用注册的binding
+	c.RegisterInstance((*map[string]Barer)(nil), map[string]Barer{})
+	_, err := c.Call(func(bars map[string]Barer) {
+		assert.Equal(t, 0, len(bars))
+	})
+	assert.Nil(t, err)
+}
+
+func TestContainer_MapParameterNotFound(t *testing.T) {
+	defer Reset()
+	Register(func(fooers map[string]Fooer) *barRouter { return &barRouter{} })
+	var router *barRouter
+	err := Resolve(&router)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.Is(Validate(), ErrNotFound))
+
+	Register(func(fooers map[string]Fooer) *barRouter { return &barRouter{} }, Optional(0), Name("optional"))
+	assert.Nil(t, Resolve(&router, ResolveName("optional")))
+}
+
+func TestContainer_FillMap(t *testing.T) {
+	parent := NewContainer()
+	parent.Register(func() Barer { return &namedBar{name: "parent a"} }, Name("a"))
+	parent.Register(func() Barer { return &namedBar{name: "parent b"} }, Name("b"))
+	child := parent.NewChild()
+	child.Register(func() Barer { return &namedBar{name: "child a"} }, Name("a"))
+	type router struct {
+		Bars   map[string]Barer
+		Fooers map[string]Fooer `optional:"true"`
+	}
+	r := &router{}
+	assert.Nil(t, child.Fill(r))
+	assert.Equal(t, 2, len(r.Bars))
+	assert.Equal(t, "child a", r.Bars["a"].(*namedBar).name)
+	assert.Equal(t, "parent b", r.Bars["b"].(*namedBar).name)
+	assert.Nil(t, r.Fooers)
+}
@@ -0,0 +1,131 @@
+package iocgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFoobarModule() *Module {
+	return NewModule("foobar").
+		Register(func() Fooer { return &Foo{} }).
+		Register(func() Barer { return &namedBar{name: "private"} }).
+		Register(NewFoobar).
+		Export((*Foobarer)(nil))
+}
+
+func TestContainer_Install(t *testing.T) {
+	c := NewContainer()
+	assert.Nil(t, c.Install(newFoobarModule()))
+	var fb Foobarer
+	assert.Nil(t, c.Resolve(&fb))
+	assert.Equal(t, "private", fb.(*Foobar).bar.(*namedBar).name)
+	var fb2 Foobarer
+	assert.Nil(t, c.Resolve(&fb2))
+	assert.True(t, fb == fb2)
+
+	//未导出的binding对安装模块的容器不可见
+	var bar Barer
+	assert.True(t, errors.Is(c.Resolve(&bar), ErrNotFound))
+	assert.Nil(t, c.Validate())
+	assert.Nil(t, c.CheckCycles())
+
+	err := c.Install(newFoobarModule())
+	assert.True(t, errors.Is(err, ErrDuplicateModule))
+	err = c.NewChild().Install(newFoobarModule())
+	assert.True(t, errors.Is(err, ErrDuplicateModule))
+}
+
+func TestContainer_InstallDependsOnContainer(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Fooer { return &Foo{} })
+	m := NewModule("bar").
+		Register(func(f Fooer) Barer { return &namedBar{name: "bar"} }).
+		Register(NewFoobar, Name("module")).
+		Export((*Foobarer)(nil))
+	assert.Nil(t, c.Install(m))
+	var fb, fb2 Foobarer
+	assert.Nil(t, c.Resolve(&fb, ResolveName("module")))
+	assert.Equal(t, "bar", fb.(*Foobar).bar.(*namedBar).name)
+	assert.Nil(t, c.Resolve(&fb2))
+	assert.True(t, fb == fb2)
+
+	//通过子容器获得导出的binding时仍然在模块中解析依赖
+	child := c.NewChild()
+	child.Register(func() Barer { return &namedBar{name: "child"} })
+	var bar Barer
+	assert.Nil(t, child.Resolve(&fb2))
+	assert.True(t, fb == fb2)
+	assert.Nil(t, child.Resolve(&bar))
+	assert.Equal(t, "child", bar.(*namedBar).name)
+}
+
+func TestContainer_InstallRequires(t *testing.T) {
+	c := NewContainer()
+	foo := NewModule("foo").Register(func() Fooer { return &Foo{} }).Export((*Fooer)(nil))
+	bar := NewModule("bar").
+		Requires("foo").
+		Register(func() Barer { return &Bar{} }).
+		Register(NewFoobar).
+		Export((*Foobarer)(nil))
+	err := c.Install(bar)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Nil(t, c.Install(foo, bar))
+	var fb Foobarer
+	assert.Nil(t, c.Resolve(&fb))
+
+	err = c.Install(NewModule("baz").Export((*Barer)(nil)))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	err = c.Install(NewModule("invalid").Register(&Foo{}))
+	assert.True(t, errors.Is(err, ErrInvalidConstructor))
+	//安装失败的模块可以重新安装
+	assert.Nil(t, c.Install(NewModule("baz").RegisterInstance((*Barer)(nil), &Bar{}).Export((*Barer)(nil))))
+}
+
+func TestContainer_InstallLifecycle(t *testing.T) {
+	closeLog = nil
+	c := NewContainer()
+	var started []string
+	m := NewModule("closable").
+		Register(func() Fooer { return &closableFoo{} }, OnStart(func(ctx context.Context, instance interface{}) error {
+			started = append(started, "foo")
+			return nil
+		})).
+		Register(func(f Fooer) Barer { return &namedBar{name: "bar"} }).
+		Export((*Barer)(nil))
+	assert.Nil(t, c.Install(m))
+	assert.Nil(t, c.Start(context.Background()))
+	assert.Equal(t, []string{"foo"}, started)
+	assert.Nil(t, c.Close(context.Background()))
+	assert.Equal(t, []string{"foo"}, closeLog)
+}
+
+func TestContainer_InstallClone(t *testing.T) {
+	closeLog = nil
+	c := NewContainer()
+	m := NewModule("closable").
+		Register(func() Fooer { return &closableFoo{} }).
+		Register(func() Barer { return &namedBar{name: "private"} }).
+		Register(NewFoobar).
+		Export((*Fooer)(nil), (*Foobarer)(nil))
+	assert.Nil(t, c.Install(m))
+	clone := c.Clone()
+	var foo Fooer
+	assert.Nil(t, clone.Resolve(&foo))
+	var fb Foobarer
+	assert.Nil(t, clone.Resolve(&fb))
+	assert.True(t, foo == fb.(*Foobar).foo)
+	var fb2 Foobarer
+	assert.Nil(t, c.Resolve(&fb2))
+	assert.False(t, fb == fb2)
+
+	//副本中模块的实例由副本管理
+	assert.Nil(t, c.Close(context.Background()))
+	assert.Equal(t, []string{"foo"}, closeLog)
+	closeLog = nil
+	assert.Nil(t, clone.Close(context.Background()))
+	assert.Equal(t, []string{"foo"}, closeLog)
+	assert.True(t, errors.Is(clone.Install(m), ErrDuplicateModule))
+}
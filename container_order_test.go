@@ -0,0 +1,52 @@
+package iocgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func barNames(bars []Barer) []string {
+	names := make([]string, 0, len(bars))
+	for _, b := range bars {
+		names = append(names, b.(*namedBar).name)
+	}
+	return names
+}
+
+func TestContainer_Order(t *testing.T) {
+	c := NewContainer()
+	for _, name := range []string{"e", "d", "c", "b", "a"} {
+		name := name
+		c.Register(func() Barer { return &namedBar{name: name} }, Name(name), Group("chain"))
+	}
+	c.RegisterInstance((*Barer)(nil), &namedBar{name: "first"}, Name("first"), Group("chain"), Order(-1))
+	c.Register(func() Barer { return &namedBar{name: "last"} }, Name("last"), Group("chain"), Order(10))
+	expected := []string{"first", "e", "d", "c", "b", "a", "last"}
+	for i := 0; i < 10; i++ {
+		type chain struct {
+			Bars  []Barer
+			Group []Barer `group:"chain"`
+		}
+		ch := &chain{}
+		assert.Nil(t, c.Fill(ch))
+		assert.Equal(t, expected, barNames(ch.Bars))
+		assert.Equal(t, expected, barNames(ch.Group))
+		_, err := c.Call(func(bars []Barer) {
+			assert.Equal(t, expected, barNames(bars))
+		})
+		assert.Nil(t, err)
+	}
+}
+
+func TestContainer_OrderChild(t *testing.T) {
+	parent := NewContainer()
+	parent.Register(func() Barer { return &namedBar{name: "parent"} }, Name("parent"), Group("chain"))
+	child := parent.NewChild()
+	child.Register(func() Barer { return &namedBar{name: "child"} }, Name("child"), Group("chain"), Order(-1))
+	_, err := child.Call(func(bars []Barer, group []Barer) {
+		assert.Equal(t, []string{"child", "parent"}, barNames(bars))
+		assert.Equal(t, []string{"child", "parent"}, barNames(group))
+	}, CallDependsOnGroup(map[int]string{1: "chain"}))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,131 @@
+package iocgo
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Override(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Barer { return &namedBar{name: "real"} }, Group("bars"))
+	c.Register(func() Barer { return &namedBar{name: "conditional"} }, When(func(*Container) bool { return false }))
+	restore, err := c.Override((*Barer)(nil), &namedBar{name: "fake"}, Group("bars"))
+	assert.Nil(t, err)
+	var bar Barer
+	assert.Nil(t, c.Resolve(&bar))
+	assert.Equal(t, "fake", bar.(*namedBar).name)
+	_, err = c.Call(func(bars []Barer) {
+		assert.Equal(t, []string{"real", "fake"}, barNames(bars))
+	}, CallDependsOnGroup(map[int]string{0: "bars"}))
+	assert.Nil(t, err)
+
+	restore()
+	assert.Nil(t, c.Resolve(&bar))
+	assert.Equal(t, "real", bar.(*namedBar).name)
+	_, err = c.Call(func(bars []Barer) {
+		assert.Equal(t, []string{"real"}, barNames(bars))
+	}, CallDependsOnGroup(map[int]string{0: "bars"}))
+	assert.Nil(t, err)
+
+	restore, err = c.Override((*Fooer)(nil), &Foo{})
+	assert.Nil(t, err)
+	var foo Fooer
+	assert.Nil(t, c.Resolve(&foo))
+	restore()
+	assert.NotNil(t, c.Resolve(&foo))
+}
+
+func TestContainer_OverrideResetsSingletons(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} })
+	c.Register(NewFoobar)
+	restore, err := c.Override((*Barer)(nil), &Baz{})
+	assert.Nil(t, err)
+	var fb Foobarer
+	assert.Nil(t, c.Resolve(&fb))
+	assert.IsType(t, &Baz{}, fb.(*Foobar).bar)
+
+	//替换期间构造的单例在恢复后重新构造
+	restore()
+	assert.Nil(t, c.Resolve(&fb))
+	assert.IsType(t, &Bar{}, fb.(*Foobar).bar)
+	var fb2 Foobarer
+	assert.Nil(t, c.Resolve(&fb2))
+	assert.True(t, fb == fb2)
+
+	//替换之前构造的单例不受影响
+	restore, err = c.Override((*Barer)(nil), &Baz{})
+	assert.Nil(t, err)
+	assert.Nil(t, c.Resolve(&fb2))
+	assert.True(t, fb == fb2)
+	restore()
+	assert.Nil(t, c.Resolve(&fb2))
+	assert.True(t, fb == fb2)
+}
+
+func TestContainer_OverrideResetsChildSingletons(t *testing.T) {
+	parent := NewContainer()
+	parent.Register(func() Fooer { return &Foo{} })
+	parent.Register(NewFoobar)
+	child := parent.NewChild()
+	child.Register(func() Barer { return &Bar{} })
+	restore, err := child.Override((*Barer)(nil), &Baz{})
+	assert.Nil(t, err)
+	var fb Foobarer
+	assert.Nil(t, child.Resolve(&fb))
+	assert.IsType(t, &Baz{}, fb.(*Foobar).bar)
+	restore()
+	assert.Nil(t, child.Resolve(&fb))
+	assert.IsType(t, &Bar{}, fb.(*Foobar).bar)
+}
+
+func TestContainer_SnapshotRestore(t *testing.T) {
+	defer Reset()
+	Register(func() Barer { return &namedBar{name: "global"} })
+	snapshot := Snapshot()
+	Reset()
+	var bar Barer
+	assert.NotNil(t, Resolve(&bar))
+	Restore(snapshot)
+	assert.Nil(t, Resolve(&bar))
+	assert.Equal(t, "global", bar.(*namedBar).name)
+}
+
+func TestContainer_RestoreConcurrently(t *testing.T) {
+	defer Reset()
+	Register(func() Barer { return &namedBar{name: "global"} })
+	snapshot := Snapshot()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			Restore(snapshot)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		var bar Barer
+		assert.Nil(t, Resolve(&bar))
+		assert.Equal(t, "global", bar.(*namedBar).name)
+	}
+	<-done
+}
+
+func TestContainer_Observe(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} }, Name("bar"))
+	c.Register(NewFoobar, DependsOn(map[int]string{1: "bar"}))
+	var resolved []string
+	cancel := c.Observe(func(t reflect.Type, name string) {
+		resolved = append(resolved, describe(t, name))
+	})
+	var fb Foobarer
+	assert.Nil(t, c.NewChild().Resolve(&fb))
+	assert.Equal(t, []string{"iocgo.Foobarer", "iocgo.Fooer", "iocgo.Barer(name=bar)"}, resolved)
+	cancel()
+	assert.Nil(t, c.Resolve(&fb))
+	assert.Equal(t, 3, len(resolved))
+}
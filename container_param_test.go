@@ -0,0 +1,78 @@
+package iocgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type foobarParams struct {
+	In
+	Foo      Fooer   `name:"foo"`
+	Bar      Barer   `name:"missing" optional:"true"`
+	Handlers []Barer `group:"handlers"`
+	Lazy     Lazy[Foobarer2]
+	ignored  Barer
+}
+
+func TestContainer_ParameterObject(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Fooer { return &Foo{} }, Name("foo"))
+	c.Register(func() Barer { return &namedBar{name: "handler"} }, Name("handler"), Group("handlers"))
+	var params foobarParams
+	c.Register(func(p foobarParams) Foobarer {
+		params = p
+		return &Foobar{foo: p.Foo, bar: p.Bar}
+	})
+	var fb Foobarer
+	assert.Nil(t, c.Resolve(&fb))
+	assert.NotNil(t, params.Foo)
+	assert.Nil(t, params.Bar)
+	assert.Nil(t, params.ignored)
+	assert.Equal(t, 1, len(params.Handlers))
+	_, err := params.Lazy.Get()
+	assert.True(t, errors.Is(err, ErrNotFound))
+	//延迟获取的Foobarer2找不到
+	err = c.Validate()
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, "container: resolve iocgo.Foobarer [iocgo.Foobarer -> iocgo.Foobarer2]: not found", err.Error())
+
+	_, err = c.Call(func(p foobarParams) {
+		assert.NotNil(t, p.Foo)
+	})
+	assert.Nil(t, err)
+}
+
+func TestContainer_ParameterObjectNotFound(t *testing.T) {
+	defer Reset()
+	type params struct {
+		In
+		Foo Fooer
+	}
+	Register(func(p params) Foobarer { return &Foobar{foo: p.Foo} })
+	var fb Foobarer
+	err := Resolve(&fb)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	var re *ResolutionError
+	assert.True(t, errors.As(err, &re))
+	assert.Equal(t, []string{"iocgo.Foobarer", "iocgo.Fooer"}, re.Path)
+	assert.True(t, errors.Is(Validate(), ErrNotFound))
+
+	g := DependencyGraph()
+	assert.Equal(t, 1, len(g.Edges))
+	assert.Equal(t, "iocgo.Fooer", g.Edges[0].To)
+	assert.True(t, g.Edges[0].Missing)
+}
+
+func TestContainer_ParameterObjectCycle(t *testing.T) {
+	c := NewContainer()
+	type params struct {
+		In
+		Foobar Foobarer
+	}
+	c.Register(func(p params) Foobarer { return p.Foobar })
+	assert.True(t, errors.Is(c.CheckCycles(), ErrCircularDependency))
+	var fb Foobarer
+	assert.True(t, errors.Is(c.Resolve(&fb), ErrCircularDependency))
+}
@@ -0,0 +1,113 @@
+package iocgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Profile(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Barer { return &namedBar{name: "prod"} })
+	c.Register(func() Barer { return &namedBar{name: "test"} }, Profile("test"))
+	var bar Barer
+	assert.Nil(t, c.Resolve(&bar))
+	assert.Equal(t, "prod", bar.(*namedBar).name)
+
+	c = NewContainer()
+	c.Register(func() Barer { return &namedBar{name: "prod"} })
+	c.Register(func() Barer { return &namedBar{name: "test"} }, Profile("test", "dev"))
+	c.ActivateProfiles("dev")
+	assert.Nil(t, c.Resolve(&bar))
+	assert.Equal(t, "test", bar.(*namedBar).name)
+	assert.Equal(t, []string{"dev"}, c.ActiveProfiles())
+	bars := []Barer{}
+	_, err := c.Call(func(all []Barer) { bars = all })
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"test"}, barNames(bars))
+}
+
+func TestContainer_ProfileOnly(t *testing.T) {
+	defer Reset()
+	Register(func() Barer { return &namedBar{name: "test"} }, Profile("test"))
+	var bar Barer
+	assert.True(t, errors.Is(Resolve(&bar), ErrNotFound))
+
+	t.Setenv(ProfilesEnv, "prod, test")
+	assert.Nil(t, Resolve(&bar))
+	assert.Equal(t, "test", bar.(*namedBar).name)
+	assert.Equal(t, []string{"prod", "test"}, container.ActiveProfiles())
+}
+
+func TestContainer_ProfileNamedDefault(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Barer { return &namedBar{name: "redis"} }, Name("redis"), Profile("prod"))
+	c.Register(func() Barer { return &namedBar{name: "memcached"} }, Name("memcached"), Profile("prod"))
+	var bar Barer
+	assert.True(t, errors.Is(c.Resolve(&bar), ErrNotFound))
+
+	//没有不带条件的binding时，先注册的带有条件的binding作为默认binding
+	c.ActivateProfiles("prod")
+	assert.Nil(t, c.Resolve(&bar))
+	assert.Equal(t, "redis", bar.(*namedBar).name)
+	assert.Nil(t, c.NewChild().Resolve(&bar))
+	assert.Equal(t, "redis", bar.(*namedBar).name)
+	assert.Nil(t, c.Resolve(&bar, ResolveName("memcached")))
+	assert.Equal(t, "memcached", bar.(*namedBar).name)
+	assert.Nil(t, c.Validate())
+}
+
+func TestContainer_ProfileInChild(t *testing.T) {
+	parent := NewContainer()
+	parent.Register(func() Barer { return &namedBar{name: "parent"} })
+	parent.ActivateProfiles("test")
+	child := parent.NewChild()
+	child.Register(func() Barer { return &namedBar{name: "child"} }, Profile("test"), Name("a"))
+	var bar Barer
+	assert.Nil(t, child.Resolve(&bar, ResolveName("a")))
+	assert.Equal(t, "child", bar.(*namedBar).name)
+	assert.Nil(t, child.Resolve(&bar))
+	assert.Equal(t, "parent", bar.(*namedBar).name)
+
+	clone := child.Clone()
+	assert.Nil(t, clone.Resolve(&bar, ResolveName("a")))
+	assert.Equal(t, "child", bar.(*namedBar).name)
+}
+
+func TestContainer_ConditionalOnMissing(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Barer { return &namedBar{name: "fallback"} }, ConditionalOnMissing((*Barer)(nil)))
+	c.Register(func() Barer { return &namedBar{name: "second fallback"} }, ConditionalOnMissing((*Barer)(nil)))
+	var bar Barer
+	assert.Nil(t, c.Resolve(&bar))
+	assert.Equal(t, "fallback", bar.(*namedBar).name)
+
+	c = NewContainer()
+	c.Register(func() Barer { return &namedBar{name: "fallback"} }, ConditionalOnMissing((*Barer)(nil)))
+	c.RegisterInstance((*Barer)(nil), &namedBar{name: "custom"})
+	assert.Nil(t, c.Resolve(&bar))
+	assert.Equal(t, "custom", bar.(*namedBar).name)
+	assert.Nil(t, c.Validate())
+
+	err := c.Register(func() Barer { return &namedBar{} }, ConditionalOnMissing(nil))
+	assert.NotNil(t, err)
+}
+
+func TestContainer_When(t *testing.T) {
+	c := NewContainer()
+	c.AddPropertySource(MapSource{"cache.enabled": "true"})
+	enabled := func(c *Container) bool {
+		v, _ := c.Property("cache.enabled")
+		return v == "true"
+	}
+	c.Register(func() Barer { return &namedBar{name: "cache"} }, When(enabled), Group("bars"))
+	c.Register(func() Barer { return &namedBar{name: "noop"} }, When(func(*Container) bool { return false }), Group("bars"))
+	var bar Barer
+	assert.Nil(t, c.Resolve(&bar))
+	assert.Equal(t, "cache", bar.(*namedBar).name)
+	_, err := c.Call(func(bars []Barer) {
+		assert.Equal(t, []string{"cache"}, barNames(bars))
+	}, CallDependsOnGroup(map[int]string{0: "bars"}))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,95 @@
+package iocgo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_PropertyParameter(t *testing.T) {
+	log = ""
+	c := NewContainer()
+	c.AddPropertySource(MapSource{"foobar.msg": "studyzy"})
+	c.Register(NewFoobarWithMsg, Values(map[int]string{2: "foobar.msg"}))
+	c.Register(func() Fooer { return &Foo{} })
+	c.Register(func() Barer { return &Bar{} })
+	var fb Foobarer
+	assert.Nil(t, c.Resolve(&fb))
+	assert.Equal(t, "studyzy", fb.(*Foobar).msg)
+	assert.Nil(t, c.Validate())
+
+	//后添加的来源优先
+	c.AddPropertySource(MapSource{"foobar.msg": "override"})
+	_, err := c.Call(func(msg string) {
+		assert.Equal(t, "override", msg)
+	}, CallValues(map[int]string{0: "foobar.msg"}))
+	assert.Nil(t, err)
+}
+
+type serverConfig struct {
+	Addr     string        `value:"server.addr"`
+	Port     int           `value:"server.port"`
+	Debug    bool          `value:"server.debug"`
+	Timeout  time.Duration `value:"server.timeout"`
+	Ratio    float64       `value:"server.ratio"`
+	Hosts    []string      `value:"server.hosts"`
+	Ports    []uint16      `value:"server.ports"`
+	Missing  string        `value:"server.missing" optional:"true"`
+	internal int           `value:"server.port"`
+}
+
+func TestContainer_FillProperty(t *testing.T) {
+	defer Reset()
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"server":{"addr":"localhost","port":8080,"debug":true,
+		"timeout":"3s","ratio":0.5,"hosts":["a","b"],"ports":"80, 443"}}`), 0644))
+	source, err := NewJSONFileSource(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "b", source["server.hosts.1"])
+	AddPropertySource(source)
+	cfg := &serverConfig{}
+	assert.Nil(t, Fill(cfg))
+	assert.Equal(t, serverConfig{Addr: "localhost", Port: 8080, Debug: true, Timeout: 3 * time.Second, Ratio: 0.5,
+		Hosts: []string{"a", "b"}, Ports: []uint16{80, 443}, internal: 8080}, *cfg)
+}
+
+func TestContainer_EnvProperty(t *testing.T) {
+	t.Setenv("IOCGO_DB_MAX_CONNS", "16")
+	c := NewContainer()
+	c.AddPropertySource(NewEnvSource("iocgo"))
+	child := c.NewChild()
+	child.AddPropertySource(MapSource{"db.dsn": "mysql://"})
+	type params struct {
+		In
+		MaxConns int    `value:"db.max-conns"`
+		DSN      string `value:"db.dsn"`
+	}
+	_, err := child.Call(func(p params) {
+		assert.Equal(t, 16, p.MaxConns)
+		assert.Equal(t, "mysql://", p.DSN)
+	})
+	assert.Nil(t, err)
+}
+
+func TestContainer_PropertyError(t *testing.T) {
+	c := NewContainer()
+	c.AddPropertySource(MapSource{"port": "http"})
+	c.Register(func(port int) Fooer { return &Foo{} }, Values(map[int]string{0: "port"}))
+	c.Register(func(dsn string) Barer { return &Bar{} }, Values(map[int]string{0: "db.dsn"}))
+	var f Fooer
+	err := c.Resolve(&f)
+	assert.True(t, errors.Is(err, ErrInvalidProperty))
+	t.Log(err)
+	var b Barer
+	err = c.Resolve(&b)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, "container: resolve iocgo.Barer [iocgo.Barer -> property db.dsn]: not found", err.Error())
+	assert.True(t, errors.Is(c.Validate(), ErrNotFound))
+
+	_, err = NewJSONSource([]byte("{"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,185 @@
+package iocgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type foobarResult struct {
+	Out
+	Foo     Fooer
+	Bar     Barer `name:"bar"`
+	Handler Barer `name:"handler" group:"handlers"`
+	ignored Barer
+}
+
+func TestContainer_ResultObject(t *testing.T) {
+	c := NewContainer()
+	called := 0
+	c.Register(func() (foobarResult, error) {
+		called++
+		return foobarResult{Foo: &Foo{}, Bar: &Bar{}, Handler: &namedBar{name: "handler"}}, nil
+	})
+	var f Fooer
+	assert.Nil(t, c.Resolve(&f))
+	var b Barer
+	assert.Nil(t, c.Resolve(&b, ResolveName("bar")))
+	_, ok := b.(*Bar)
+	assert.True(t, ok)
+	_, err := c.Call(func(handlers []Barer) {
+		assert.Equal(t, 1, len(handlers))
+		assert.Equal(t, "handler", handlers[0].(*namedBar).name)
+	}, CallDependsOnGroup(map[int]string{0: "handlers"}))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, called)
+	//未导出的字段和标记不会注册
+	_, err = c.Call(func(bars map[string]Barer) {
+		assert.Equal(t, 2, len(bars))
+	})
+	assert.Nil(t, err)
+
+	//关闭后重新构造
+	assert.Nil(t, c.Close(context.Background()))
+	assert.Nil(t, c.Resolve(&f))
+	assert.Nil(t, c.Resolve(&b, ResolveName("bar")))
+	assert.Equal(t, 2, called)
+
+	clone := c.Clone()
+	var cf Fooer
+	assert.Nil(t, clone.Resolve(&cf))
+	assert.True(t, f == cf)
+	assert.Equal(t, 2, called)
+}
+
+func TestContainer_ResultObjectTransient(t *testing.T) {
+	defer Reset()
+	called := 0
+	Register(func() foobarResult {
+		called++
+		return foobarResult{Foo: &Foo{}, Bar: &namedBar{}}
+	}, Lifestyle(true))
+	var b1, b2 Barer
+	assert.Nil(t, Resolve(&b1, ResolveName("bar")))
+	assert.Nil(t, Resolve(&b2, ResolveName("bar")))
+	assert.False(t, b1 == b2)
+	assert.Equal(t, 2, called)
+}
+
+func TestContainer_ResultObjectError(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() (foobarResult, error) {
+		return foobarResult{}, errors.New("build failed")
+	})
+	var f Fooer
+	err := c.Resolve(&f)
+	assert.NotNil(t, err)
+	assert.Equal(t, "build failed", errors.Unwrap(err).Error())
+
+	//依赖自己的另一个结果构成循环
+	c.Register(func(f Fooer) foobarResult { return foobarResult{} }, Default())
+	var b Barer
+	err = c.Resolve(&b, ResolveName("bar"))
+	assert.True(t, errors.Is(err, ErrCircularDependency))
+}
+
+func TestContainer_MultipleResults(t *testing.T) {
+	c := NewContainer()
+	called := 0
+	c.Register(func() (Fooer, Barer, error) {
+		called++
+		return &Foo{}, &namedBar{name: "bar"}, nil
+	})
+	var f Fooer
+	assert.Nil(t, c.Resolve(&f))
+	_, ok := f.(*Foo)
+	assert.True(t, ok)
+	var b Barer
+	assert.Nil(t, c.Resolve(&b))
+	assert.Equal(t, "bar", b.(*namedBar).name)
+	assert.Equal(t, 1, called)
+
+	//error不注册为binding
+	var err error
+	assert.True(t, errors.Is(c.Resolve(&err), ErrNotFound))
+	assert.Equal(t, 2, len(c.DependencyGraph().Nodes))
+}
+
+func TestContainer_MultipleResultsError(t *testing.T) {
+	defer Reset()
+	called := 0
+	Register(func() (Fooer, Barer, error) {
+		called++
+		return nil, nil, errors.New("build failed")
+	})
+	var b Barer
+	err := Resolve(&b)
+	assert.Equal(t, "build failed", errors.Unwrap(err).Error())
+	var f Fooer
+	err = Resolve(&f)
+	assert.Equal(t, "build failed", errors.Unwrap(err).Error())
+	assert.Equal(t, 2, called)
+}
+
+func TestContainer_MultipleResultsTransient(t *testing.T) {
+	c := NewContainer()
+	called := 0
+	c.Register(func() (Fooer, Barer) {
+		called++
+		return &Foo{}, &namedBar{}
+	}, Lifestyle(true))
+	var b1, b2 Barer
+	assert.Nil(t, c.Resolve(&b1))
+	assert.Nil(t, c.Resolve(&b2))
+	assert.False(t, b1 == b2)
+	assert.Equal(t, 2, called)
+}
+
+func TestContainer_MultipleResultsScoped(t *testing.T) {
+	c := NewContainer()
+	called := 0
+	c.Register(func() (Fooer, Barer) {
+		called++
+		return &Foo{}, &namedBar{name: "scoped"}
+	}, Scoped())
+	resolveAll := func(s *Scope) Barer {
+		var f Fooer
+		var b Barer
+		assert.Nil(t, s.Resolve(&f))
+		assert.Nil(t, s.Resolve(&b))
+		return b
+	}
+	scope1, scope2 := c.NewScope(), c.NewScope()
+	b1 := resolveAll(scope1)
+	assert.Equal(t, 1, called)
+	b2 := resolveAll(scope2)
+	assert.Equal(t, 2, called)
+	assert.False(t, b1 == b2)
+	//作用域关闭后重新构造
+	assert.Nil(t, scope1.Close(context.Background()))
+	resolveAll(scope1)
+	assert.Equal(t, 3, called)
+}
+
+func TestContainer_MultipleResultsOverriddenInChild(t *testing.T) {
+	parent := NewContainer()
+	parent.Register(func() Fooer { return &Foo{} })
+	called := 0
+	parent.Register(func(f Fooer) (Barer, Foobarer) {
+		called++
+		return &namedBar{name: "parent"}, &Foobar{foo: f}
+	})
+	child := parent.NewChild()
+	child.Register(func() Fooer { return &closableFoo{} })
+	var b Barer
+	var fb Foobarer
+	assert.Nil(t, child.Resolve(&b))
+	assert.Nil(t, child.Resolve(&fb))
+	assert.Equal(t, 1, called)
+	_, ok := fb.(*Foobar).foo.(*closableFoo)
+	assert.True(t, ok)
+	assert.Nil(t, parent.Resolve(&fb))
+	assert.Equal(t, 2, called)
+}
@@ -0,0 +1,56 @@
+package iocgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Scoped(t *testing.T) {
+	closeLog = nil
+	defer Reset()
+	Register(NewFoobar, Lifestyle(true))
+	Register(func() Fooer { return &Foo{} })
+	Register(func() Barer { return &disposableBar{} }, Scoped())
+	var fb Foobarer
+	err := Resolve(&fb)
+	assert.NotNil(t, err) //作用域对象不能在容器中直接获得
+	t.Log(err)
+
+	scope1 := NewScope()
+	var b1, b2 Barer
+	var f1, f2 Fooer
+	assert.Nil(t, scope1.Resolve(&b1))
+	assert.Nil(t, scope1.Resolve(&b2))
+	assert.Nil(t, scope1.Resolve(&f1))
+	assert.True(t, b1 == b2)
+	err = scope1.Resolve(&fb)
+	assert.Nil(t, err)
+	assert.True(t, fb.(*Foobar).bar == b1)
+
+	scope2 := NewScope()
+	var b3 Barer
+	assert.Nil(t, scope2.Resolve(&b3))
+	assert.Nil(t, scope2.Resolve(&f2))
+	assert.False(t, b1 == b3)
+	assert.True(t, f1 == f2) //单例在作用域之间共享
+
+	assert.Nil(t, scope1.Close(context.Background()))
+	assert.Equal(t, []string{"bar"}, closeLog)
+	assert.Nil(t, scope2.Close(context.Background()))
+	assert.Equal(t, []string{"bar", "bar"}, closeLog)
+}
+
+func TestContainer_ScopedCaptiveDependency(t *testing.T) {
+	c := NewContainer()
+	c.Register(NewFoobar)
+	c.Register(func() Fooer { return &Foo{} }, Scoped())
+	c.Register(func() Barer { return &Bar{} })
+	scope := c.NewScope()
+	defer scope.Close(context.Background())
+	var fb Foobarer
+	err := scope.Resolve(&fb) //单例不能依赖作用域对象
+	assert.NotNil(t, err)
+	t.Log(err)
+}
@@ -0,0 +1,95 @@
+package iocgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type taggedFoobar struct {
+	Foo     Fooer            `name:"foo"`
+	Bar     Barer            `optional:"true"`
+	Bars    []Barer          `group:"bars"`
+	Fooers  map[string]Fooer `optional:"true"`
+	Msg     string           `value:"foobar.msg"`
+	Version int
+}
+
+func TestContainer_RegisterType(t *testing.T) {
+	defer Reset()
+	Register(func() Fooer { return &Foo{} })
+	Register(func() Barer { return &namedBar{name: "bar"} })
+	var fb Foobarer
+	assert.Nil(t, RegisterType(&Foobar{msg: "prototype"}, Interface(&fb)))
+	assert.Nil(t, Resolve(&fb))
+	foobar := fb.(*Foobar)
+	assert.NotNil(t, foobar.foo)
+	assert.Equal(t, "bar", foobar.bar.(*namedBar).name)
+	assert.Equal(t, "prototype", foobar.msg)
+	var fb2 Foobarer
+	assert.Nil(t, Resolve(&fb2))
+	assert.True(t, fb == fb2)
+	assert.Nil(t, Validate())
+}
+
+func TestContainer_RegisterTypeTags(t *testing.T) {
+	c := NewContainer()
+	c.AddPropertySource(MapSource{"foobar.msg": "hello"})
+	c.Register(func() Fooer { return &Foo{} }, Name("foo"))
+	c.Register(func() Barer { return &namedBar{name: "a"} }, Group("bars"), Name("a"))
+	c.Register(func() Barer { return &namedBar{name: "b"} }, Group("bars"), Name("b"))
+	assert.Nil(t, c.RegisterType(taggedFoobar{Version: 2}, Lifestyle(true)))
+	var tagged taggedFoobar
+	assert.Nil(t, c.Resolve(&tagged))
+	assert.NotNil(t, tagged.Foo)
+	assert.Equal(t, "a", tagged.Bar.(*namedBar).name)
+	assert.Equal(t, []string{"a", "b"}, barNames(tagged.Bars))
+	assert.Equal(t, 1, len(tagged.Fooers))
+	assert.Equal(t, "hello", tagged.Msg)
+	assert.Equal(t, 2, tagged.Version)
+
+	assert.Nil(t, RegisterTypeOf[taggedFoobar](c))
+	var ptr *taggedFoobar
+	assert.Nil(t, c.Resolve(&ptr))
+	assert.Equal(t, "hello", ptr.Msg)
+	assert.Equal(t, 0, ptr.Version)
+}
+
+type optionalTagged struct {
+	Bar Barer `name:"x"`
+	Foo Fooer `optional:"true"`
+}
+
+func TestContainer_RegisterTypeTagsWithOptions(t *testing.T) {
+	c := NewContainer()
+	c.Register(func() Barer { return &namedBar{name: "default"} })
+	c.Register(func() Barer { return &namedBar{name: "x"} }, Name("x"))
+	c.Register(func() Barer { return &namedBar{name: "y"} }, Name("y"))
+	//选项与标签合并，不会覆盖其他字段的标签
+	assert.Nil(t, c.RegisterType(optionalTagged{}, Optional(0)))
+	var s optionalTagged
+	assert.Nil(t, c.Resolve(&s))
+	assert.Equal(t, "x", s.Bar.(*namedBar).name)
+	assert.Nil(t, s.Foo)
+
+	//同一个字段以选项的指定为准
+	c = c.NewChild()
+	assert.Nil(t, c.RegisterType(optionalTagged{}, DependsOn(map[int]string{0: "y"})))
+	assert.Nil(t, c.Resolve(&s))
+	assert.Equal(t, "y", s.Bar.(*namedBar).name)
+	assert.Nil(t, s.Foo)
+}
+
+func TestContainer_RegisterTypeError(t *testing.T) {
+	c := NewContainer()
+	err := c.RegisterType(NewFoobar)
+	assert.True(t, errors.Is(err, ErrInvalidConstructor))
+	err = c.RegisterType(nil)
+	assert.True(t, errors.Is(err, ErrInvalidConstructor))
+
+	assert.Nil(t, c.RegisterType(&Foobar{}, Interface((*Foobarer)(nil))))
+	var fb Foobarer
+	assert.True(t, errors.Is(c.Resolve(&fb), ErrNotFound))
+	assert.True(t, errors.Is(c.Validate(), ErrNotFound))
+}
@@ -0,0 +1,46 @@
+package iocgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Validate(t *testing.T) {
+	defer Reset()
+	Register(NewFoobarWithMsg, Parameters(map[int]interface{}{2: "studyzy"}))
+	Register(NewSubFoobar, Name("sub"))
+	Register(NewFoobarError, Name("error"), Optional(0), DependsOn(map[int]string{1: "baz"}))
+	Register(NewFoobarWithInputTag, Name("input"), Parameters(map[int]interface{}{0: &FoobarInputWithTag{}}))
+	Register(func() Fooer { return &Foo{} })
+	Register(func() Barer { return &Bar{} }, Name("bar"))
+	Register(func() Barer { return &Baz{} }, Name("baz"))
+	var sub SubFooer
+	var foo Fooer
+	RegisterSubInterface(&sub, &foo)
+	err := Validate()
+	assert.Nil(t, err)
+
+	Register(func(f Foobarer2) Barer { return &Bar2{} }, Name("bar2"))
+	Register(func(s string) Fooer { return &Foo{} }, Name("foo"), Scoped())
+	Register(NewFoobar, Name("scoped"), DependsOn(map[int]string{0: "foo"}))
+	err = Validate()
+	assert.NotNil(t, err)
+	t.Log(err)
+	var multi MultiError
+	assert.True(t, errors.As(err, &multi))
+	assert.Equal(t, 3, len(multi))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.Is(err, ErrNoScope))
+}
+
+func TestContainer_ValidateCycle(t *testing.T) {
+	c := NewContainer()
+	c.Register(NewFoobar)
+	c.Register(func(fb Foobarer) Fooer { return &Foo{} })
+	c.Register(func(f Fooer) Barer { return &Bar{} }, Optional(0))
+	err := c.Validate()
+	assert.True(t, errors.Is(err, ErrCircularDependency))
+	t.Log(err)
+}
@@ -0,0 +1,167 @@
+package iocgo
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// 通过ResolveContext、CallContext和FillContext获得实例时，调用者传入的ctx会注入到构造函数、装饰器以及Call的函数中
+// context.Context类型的参数，这些参数没有通过DependsOn指定名字时不需要注册binding。不传入ctx时，
+// 如果没有注册context.Context的binding，注入的是context.Background()。
+// ctx取消或者超时后不再构造新的实例，并立即返回ctx的错误，不会等待正在执行的构造函数，
+// 这些构造函数在后台执行完成后，构造的单例仍然会被容器缓存。
+// 单例的生命周期比一次请求长，它以及它的依赖的构造函数得到的是与调用者的ctx分离的ctx，
+// 其中的值与调用者的ctx相同，但是不会被取消也没有超时时间，只有瞬态和作用域对象得到调用者的ctx
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// contextValue 获得类型为context.Context的ctx
+func contextValue(ctx context.Context) reflect.Value {
+	return reflect.ValueOf(&ctx).Elem()
+}
+
+// detachedContext 保留ctx中的值，但是不会随ctx被取消或者超时，用于构造单例
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+// detach 获得与ctx分离的ctx，ctx为nil时返回nil
+func detach(ctx context.Context) context.Context {
+	if ctx == nil {
+		return nil
+	}
+	if _, ok := ctx.(detachedContext); ok {
+		return ctx
+	}
+	return detachedContext{ctx}
+}
+
+// await 在新的goroutine中执行resolve，ctx取消或者超时时不再等待，直接返回ctx的错误
+func await[T any](ctx context.Context, resolve func() (T, error)) (T, error) {
+	if ctx.Done() == nil { //不会被取消的ctx不需要新的goroutine
+		return resolve()
+	}
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := resolve()
+		done <- result{value, err}
+	}()
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// ResolveContext 与Resolve相同，但是ctx会注入到构造函数中context.Context类型的参数，
+// ctx取消或者超时时停止解析并返回ctx的错误，此时abstraction不会被修改
+func (c *Container) ResolveContext(ctx context.Context, abstraction interface{}, options ...ResolveOption) error {
+	return c.resolveContext(&resolution{ctx: ctx}, abstraction, options...)
+}
+
+func (c *Container) resolveContext(r *resolution, abstraction interface{}, options ...ResolveOption) error {
+	receiverType := reflect.TypeOf(abstraction)
+	if receiverType == nil || receiverType.Kind() != reflect.Ptr {
+		return c.resolve(r, abstraction, options...)
+	}
+	//先解析到临时变量中，避免返回之后后台的解析再修改abstraction
+	target := reflect.New(receiverType.Elem())
+	_, err := await(r.ctx, func() (struct{}, error) {
+		return struct{}{}, c.resolve(r, target.Interface(), options...)
+	})
+	if err != nil {
+		if err == r.ctx.Err() { //没有等到解析完成
+			return &ResolutionError{Type: receiverType.Elem(), Err: err}
+		}
+		return err
+	}
+	reflect.ValueOf(abstraction).Elem().Set(target.Elem())
+	return nil
+}
+
+// CallContext 与Call相同，但是ctx会注入到函数以及构造函数中context.Context类型的参数，
+// ctx取消或者超时时停止解析并返回ctx的错误，此时函数不会被调用
+func (c *Container) CallContext(ctx context.Context, function interface{}, options ...CallOption) ([]interface{}, error) {
+	return c.callContext(&resolution{ctx: ctx}, function, options...)
+}
+
+func (c *Container) callContext(r *resolution, function interface{}, options ...CallOption) ([]interface{}, error) {
+	args, err := await(r.ctx, func() ([]reflect.Value, error) {
+		return c.callArguments(r, function, options...)
+	})
+	if err == nil {
+		err = r.ctx.Err()
+	}
+	if err != nil {
+		if err == r.ctx.Err() { //没有等到解析完成
+			return nil, &ResolutionError{Type: reflect.TypeOf(function), Err: err}
+		}
+		return nil, err
+	}
+	return callFunction(function, args)
+}
+
+// FillContext 与Fill相同，但是ctx会注入到构造函数中context.Context类型的参数，
+// ctx取消或者超时时停止解析并返回ctx的错误，此时structure不会被修改
+func (c *Container) FillContext(ctx context.Context, structure interface{}) error {
+	err := c.fillContext(&resolution{ctx: ctx}, structure)
+	if _, ok := err.(*ResolutionError); ok {
+		return withRequest(err, reflect.TypeOf(structure).Elem(), "")
+	}
+	return err
+}
+
+func (c *Container) fillContext(r *resolution, structure interface{}) error {
+	receiverType := reflect.TypeOf(structure)
+	if receiverType == nil || receiverType.Kind() != reflect.Ptr || receiverType.Elem().Kind() != reflect.Struct {
+		return c.fill(r, structure)
+	}
+	//先填充struct的副本，全部成功之后再复制回structure
+	target := reflect.New(receiverType.Elem())
+	target.Elem().Set(reflect.ValueOf(structure).Elem())
+	_, err := await(r.ctx, func() (struct{}, error) {
+		return struct{}{}, c.fill(r, target.Interface())
+	})
+	if err != nil {
+		if err == r.ctx.Err() { //没有等到解析完成
+			return &ResolutionError{Err: err}
+		}
+		return err
+	}
+	reflect.ValueOf(structure).Elem().Set(target.Elem())
+	return nil
+}
+
+// ResolveContext 在作用域中获得接口对应的实例，用法与Container.ResolveContext相同
+func (s *Scope) ResolveContext(ctx context.Context, abstraction interface{}, options ...ResolveOption) error {
+	return s.container.resolveContext(&resolution{scope: s, ctx: ctx}, abstraction, options...)
+}
+
+// CallContext 在作用域中调用函数，用法与Container.CallContext相同
+func (s *Scope) CallContext(ctx context.Context, function interface{}, options ...CallOption) ([]interface{}, error) {
+	return s.container.callContext(&resolution{scope: s, ctx: ctx}, function, options...)
+}
+
+// FillContext 在作用域中填充struct的字段，用法与Container.FillContext相同
+func (s *Scope) FillContext(ctx context.Context, structure interface{}) error {
+	return s.container.fillContext(&resolution{scope: s, ctx: ctx}, structure)
+}
@@ -0,0 +1,94 @@
+package iocgo
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CycleError 表示binding之间存在循环依赖，Path是构成循环的binding链，首尾相同
+type CycleError struct {
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return "cycle: " + strings.Join(e.Path, " -> ")
+}
+
+func newCycleError(path []*binding) *CycleError {
+	names := make([]string, 0, len(path))
+	for _, b := range path {
+		names = append(names, b.String())
+	}
+	return &CycleError{Path: names}
+}
+
+// CheckCycles 不调用任何构造函数，静态检查容器中注册的所有binding之间是否存在循环依赖，
+// 每个循环都会以一个CycleError返回，多个循环时返回MultiError
+func (c *Container) CheckCycles() error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[*binding]int)
+	var stack []*binding
+	var errs []error
+	var visit func(b *binding)
+	visit = func(b *binding) {
+		state[b] = visiting
+		stack = append(stack, b)
+		resolver := b.resolver(c)
+		for _, dep := range b.dependencies(resolver) {
+			if dep.lazy { //延迟获取的依赖不会在构造时被解析，不构成循环
+				continue
+			}
+			dbs, _ := resolver.dependencyBindings(dep)
+			for _, db := range dbs {
+				switch state[db] {
+				case visiting:
+					for i := len(stack) - 1; i >= 0; i-- {
+						if stack[i] == db {
+							errs = append(errs, newCycleError(append(stack[i:len(stack):len(stack)], db)))
+							break
+						}
+					}
+				case 0:
+					visit(db)
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[b] = visited
+	}
+	for _, b := range c.allBindings() {
+		if state[b] == 0 {
+			visit(b)
+		}
+	}
+	return newMultiError(errs)
+}
+
+// allBindings 获得本容器以及所有父容器中的binding，按接口类型和名字排序，同名的binding以子容器中的为准
+func (c *Container) allBindings() []*binding {
+	types := make(map[reflect.Type]bool)
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		for t := range cur.bind {
+			types[t] = true
+		}
+		for t := range cur.conditional {
+			types[t] = true
+		}
+		cur.mu.RUnlock()
+	}
+	var bindings []*binding
+	for t := range types {
+		for _, b := range c.namedBindings(t) {
+			bindings = append(bindings, b)
+		}
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].String() < bindings[j].String()
+	})
+	return bindings
+}
@@ -0,0 +1,75 @@
+package iocgo
+
+import (
+	"reflect"
+)
+
+// Decorate 注册一个装饰器，装饰器的形式为func(inner T, 其他依赖...) T或者func(inner T, 其他依赖...) (T, error)，
+// 之后获得T对应的实例时得到的是装饰后的实例。多个装饰器按注册的顺序叠加，父容器中的装饰器先于子容器中的被应用，
+// 装饰器只对注册它的容器及其子容器中的binding生效。
+// 装饰器除第一个参数外的其他参数与构造函数一样从容器中获得，可以通过DependsOn、Optional和Parameters指定，
+// 通过Name指定时只装饰该名字的binding，否则装饰T的所有binding。
+// 装饰器应该在获得实例之前注册，已经构造完成的单例不会再被装饰
+func (c *Container) Decorate(decorator interface{}, options ...Option) error {
+	t := reflect.TypeOf(decorator)
+	if t == nil || t.Kind() != reflect.Func || t.NumIn() == 0 || t.NumOut() == 0 || t.NumOut() > 2 ||
+		t.Out(0) != t.In(0) || (t.NumOut() == 2 && t.Out(1) != errorType) {
+		return containerError(ErrInvalidDecorator, "decorator must be a function like func(inner T, deps...) T, input type: %v", t)
+	}
+	d := &binding{constructor: decorator, specifiedParameters: make(map[int]interface{}), abstract: t.In(0), owner: c}
+	for _, op := range options {
+		if err := op(d); err != nil {
+			return err
+		}
+	}
+	c.mu.Lock()
+	c.decorators[d.abstract] = append(c.decorators[d.abstract], d)
+	c.mu.Unlock()
+	return nil
+}
+
+// decorators 获得对binding b生效的装饰器，按照应用的顺序排列
+func (b *binding) decorators() []*binding {
+	var chain [][]*binding
+	for cur := b.owner; cur != nil; cur = cur.parent {
+		var ds []*binding
+		cur.mu.RLock()
+		for _, d := range cur.decorators[b.abstract] {
+			if d.name == "" || d.name == b.name {
+				ds = append(ds, d)
+			}
+		}
+		cur.mu.RUnlock()
+		chain = append(chain, ds)
+	}
+	var decorators []*binding
+	for i := len(chain) - 1; i >= 0; i-- {
+		decorators = append(decorators, chain[i]...)
+	}
+	return decorators
+}
+
+// decorate 依次应用binding b的装饰器，返回装饰后的实例
+func (c *Container) decorate(r *resolution, b *binding, instance interface{}) (interface{}, error) {
+	for _, d := range b.decorators() {
+		//被装饰的实例单独传入，避免被当作指定的struct参数Fill
+		parameters := make(map[int]interface{}, len(d.specifiedParameters)+1)
+		for i, v := range d.specifiedParameters {
+			parameters[i] = v
+		}
+		parameters[0] = nil
+		args, err := c.arguments(r, d.constructor, parameters, d.injection)
+		if err != nil {
+			return nil, err
+		}
+		if instance != nil {
+			args[0] = reflect.ValueOf(instance)
+		}
+		results, err := callFunction(d.constructor, args)
+		if err != nil { //装饰器返回的error
+			return nil, newResolutionError(r.path, "", err)
+		}
+		instance = results[0]
+	}
+	return instance, nil
+}
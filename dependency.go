@@ -0,0 +1,126 @@
+package iocgo
+
+import (
+	"reflect"
+)
+
+// injection 描述一个参数或字段如何从容器获得
+type injection struct {
+	name     string //依赖的binding的名字
+	optional bool   //找不到时是否设置为零值
+	group    string //依赖的分组
+	value    string //注入的配置项的key
+}
+
+// injection 获得构造函数第i个参数的注入方式
+func (b *binding) injection(i int) injection {
+	return injection{name: b.dependsOn[i], optional: b.optionalIndexes[i], group: b.groupDependsOn[i], value: b.values[i]}
+}
+
+// dependency 描述构造函数中一个需要从容器获得的参数
+type dependency struct {
+	injection
+	index     int
+	abstract  reflect.Type
+	lazy      bool           //是否是延迟获取的依赖或者工厂，如果是，abstract是真正依赖的类型
+	decorator bool           //是否是装饰器的参数，如果是，index是参数在装饰器中的位置
+	all       bool           //参数是接口的切片或者以名字为key的接口map，依赖abstract的所有binding
+	keyed     bool           //参数是以名字为key的接口map
+	ctx       bool           //参数是调用者传入的context.Context
+	panics    bool           //参数是不返回error的函数，获得实例失败时调用它会panic
+	args      []reflect.Type //参数是工厂时，调用工厂时传入的参数的类型
+}
+
+// dependencies 返回binding的构造函数以及装饰器中需要从容器获得的参数，通过Parameters指定了值的参数不包含在内
+func (b *binding) dependencies(c *Container) []dependency {
+	deps := b.parameters(c, 0)
+	for _, d := range b.decorators() {
+		for _, dep := range d.parameters(c, 1) { //第一个参数是被装饰的实例
+			dep.decorator = true
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// parameters 返回构造函数中从第from个参数开始需要从容器获得的参数
+func (b *binding) parameters(c *Container, from int) []dependency {
+	if b.constructor == nil {
+		return nil
+	}
+	fnType := reflect.TypeOf(b.constructor)
+	var deps []dependency
+	for i := from; i < fnType.NumIn(); i++ {
+		if _, has := b.specifiedParameters[i]; has {
+			continue
+		}
+		deps = append(deps, c.dependencyOf(i, fnType.In(i), b.injection(i))...)
+	}
+	return deps
+}
+
+// dependencyOf 按照argument的规则描述类型为t的第index个参数的依赖，参数对象的每个字段都是一个依赖
+func (c *Container) dependencyOf(index int, t reflect.Type, inj injection) []dependency {
+	dep := dependency{injection: inj, index: index, abstract: t}
+	if dep.value != "" { //配置项不是binding
+		return []dependency{dep}
+	}
+	if t == contextType && dep.name == "" && dep.group == "" { //调用者传入的context.Context不是binding
+		dep.ctx = true
+		return []dependency{dep}
+	}
+	if dep.group == "" && isParameterObject(t) {
+		return c.parameterObjectDependencies(index, t)
+	}
+	if target, ok := c.lazyTarget(dep.abstract); ok && dep.group == "" {
+		dep.abstract, dep.lazy = target, true
+		if t.Kind() == reflect.Func {
+			dep.panics = t.NumOut() == 1
+			for i := 0; i < t.NumIn(); i++ {
+				dep.args = append(dep.args, t.In(i))
+			}
+		}
+	} else if dep.group != "" && dep.abstract.Kind() == reflect.Slice {
+		dep.abstract = dep.abstract.Elem()
+	} else if _, err := c.getBinding(dep.abstract, dep.name); err != nil && (isInterfaceSlice(dep.abstract) || isInterfaceMap(dep.abstract)) && dep.name == "" {
+		dep.keyed = dep.abstract.Kind() == reflect.Map
+		dep.abstract, dep.all = dep.abstract.Elem(), true
+	}
+	return []dependency{dep}
+}
+
+// dependencyBindings 获得一个依赖对应的binding，分组或者切片依赖可能对应多个binding，找不到时返回ErrNotFound
+func (c *Container) dependencyBindings(dep dependency) ([]*binding, error) {
+	switch {
+	case dep.value != "", dep.ctx: //配置项和调用者传入的context.Context不对应binding
+		return nil, nil
+	case dep.group != "":
+		return c.groupMembers(dep.group, dep.abstract), nil
+	case dep.all:
+		bindings := c.sortedBindings(dep.abstract)
+		if len(bindings) == 0 {
+			return nil, ErrNotFound
+		}
+		return bindings, nil
+	}
+	b, err := c.getBinding(dep.abstract, dep.name)
+	if err != nil {
+		return nil, err
+	}
+	return []*binding{b}, nil
+}
+
+// describe 描述一个依赖，用于错误信息和依赖关系图
+func (dep dependency) describe() string {
+	switch {
+	case dep.value != "":
+		return "property " + dep.value
+	case dep.group != "":
+		return "group " + dep.group
+	case dep.keyed:
+		return "map[string]" + dep.abstract.String()
+	case dep.all:
+		return "[]" + dep.abstract.String()
+	}
+	return describe(dep.abstract, dep.name)
+}
@@ -0,0 +1,101 @@
+package iocgo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrNotFound 容器中找不到接口或名字对应的binding
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidAbstraction 传入的不是接口的指针，或者类型没有实现指定的接口
+	ErrInvalidAbstraction = errors.New("invalid abstraction")
+	// ErrInvalidConstructor 注册的构造函数不是函数，或者没有返回值
+	ErrInvalidConstructor = errors.New("invalid constructor")
+	// ErrInvalidFunction Call传入的不是函数，工厂的参数与构造函数的参数不匹配，或者生命周期钩子为nil
+	ErrInvalidFunction = errors.New("invalid function")
+	// ErrInvalidStructure Fill传入的不是struct的指针
+	ErrInvalidStructure = errors.New("invalid structure")
+	// ErrNoScope 作用域对象没有在Scope中获取
+	ErrNoScope = errors.New("scoped binding must be resolved in a scope")
+	// ErrInvalidDecorator 注册的装饰器不是func(inner T, deps...) T的形式
+	ErrInvalidDecorator = errors.New("invalid decorator")
+	// ErrInvalidProperty 配置项的值无法转换为需要的类型
+	ErrInvalidProperty = errors.New("invalid property")
+	// ErrDuplicateModule 同名的模块已经安装在容器中
+	ErrDuplicateModule = errors.New("module already installed")
+	// ErrCircularDependency binding之间存在循环依赖，CycleError与之匹配
+	ErrCircularDependency = errors.New("circular dependency")
+)
+
+// ResolutionError 是Resolve、Call和Fill在获得实例失败时返回的错误。
+// Type和Name是请求的类型和名字，Path是从请求的类型到失败的依赖的binding链，
+// Err是失败的原因，比如ErrNotFound、CycleError或者构造函数返回的error
+type ResolutionError struct {
+	Type reflect.Type
+	Name string
+	Path []string
+	Err  error
+}
+
+func (e *ResolutionError) Error() string {
+	msg := "container: resolve "
+	if e.Type != nil {
+		msg += e.Type.String()
+	}
+	if e.Name != "" {
+		msg += "(name=" + e.Name + ")"
+	}
+	var cycle *CycleError
+	if len(e.Path) > 1 && !errors.As(e.Err, &cycle) { //循环依赖的错误中已经包含了路径
+		msg += " [" + strings.Join(e.Path, " -> ") + "]"
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使CycleError可以通过errors.Is(err, ErrCircularDependency)判断
+func (e *CycleError) Is(target error) bool {
+	return target == ErrCircularDependency
+}
+
+// newResolutionError 在解析链path上失败时创建错误，missing是找不到的依赖的描述，为空表示path最后的binding失败
+func newResolutionError(path []*binding, missing string, err error) *ResolutionError {
+	names := make([]string, 0, len(path)+1)
+	for _, b := range path {
+		names = append(names, b.String())
+	}
+	if missing != "" {
+		names = append(names, missing)
+	}
+	return &ResolutionError{Path: names, Err: err}
+}
+
+// withRequest 为解析过程中产生的错误填充请求的类型和名字，其他错误会被包装为ResolutionError
+func withRequest(err error, t reflect.Type, name string) error {
+	if re, ok := err.(*ResolutionError); ok {
+		if re.Type == nil {
+			re.Type, re.Name = t, name
+		}
+		return re
+	}
+	return &ResolutionError{Type: t, Name: name, Err: err}
+}
+
+// describe 描述一个类型和名字，格式与binding.String相同
+func describe(t reflect.Type, name string) string {
+	if name != "" {
+		return t.String() + "(name=" + name + ")"
+	}
+	return t.String()
+}
+
+// containerError 创建一个包装了sentinel的错误，可以通过errors.Is判断
+func containerError(sentinel error, format string, a ...interface{}) error {
+	return fmt.Errorf("container: %w: "+format, append([]interface{}{sentinel}, a...)...)
+}
@@ -0,0 +1,84 @@
+package iocgo
+
+import (
+	"reflect"
+)
+
+// 构造函数可以声明func(参数...) T或func(参数...) (T, error)类型的参数，容器会注入一个工厂函数。
+// 每次调用工厂都会使用T对应的构造函数创建一个新的实例：调用时传入的参数按类型依次匹配构造函数中
+// 还未匹配的参数，与Resolve时通过Arguments指定参数值的效果相同，其余的参数仍然从容器中获得。
+// 例如NewFoobarWithMsg(f Fooer, b Barer, msg string)注册为Foobarer后，
+// 其他构造函数可以声明func(msg string) Foobarer类型的参数来创建带有不同msg的Foobarer
+
+// factoryValue 为工厂类型t创建注入的函数，target是工厂创建的实例的类型，name是对应的binding的名字
+func (c *Container) factoryValue(r *resolution, t reflect.Type, target reflect.Type, name string) reflect.Value {
+	return reflect.MakeFunc(t, func(in []reflect.Value) []reflect.Value {
+		inst, err := c.assist(r, target, name, in)
+		return funcResults(t, target, inst, err)
+	})
+}
+
+// assist 使用工厂调用时传入的参数in创建一个新的实例并应用装饰器，不论binding的生命周期是什么，都不会缓存该实例
+func (c *Container) assist(r *resolution, target reflect.Type, name string, in []reflect.Value) (interface{}, error) {
+	if r.isFinished() {
+		r = &resolution{scope: r.scope}
+	}
+	b, err := c.getBinding(target, name)
+	if err != nil {
+		return nil, withRequest(newResolutionError(r.path, describe(target, name), err), target, name)
+	}
+	if b.constructor == nil {
+		return nil, withRequest(containerError(ErrInvalidConstructor, "%s is registered as instance, factory needs a constructor", b), target, name)
+	}
+	parameters, err := b.assistedParameters(in)
+	if err != nil {
+		return nil, withRequest(err, target, name)
+	}
+	next, err := r.enter(b)
+	if err != nil {
+		return nil, withRequest(newResolutionError(r.path, "", err), target, name)
+	}
+	inst, err := b.create(b.resolver(c), next, parameters, nil, nil)
+	if err != nil {
+		return nil, withRequest(err, target, name)
+	}
+	return inst, nil
+}
+
+// assistedParameters 将工厂调用时传入的参数按类型匹配到构造函数的参数上，与注册时指定的参数合并
+func (b *binding) assistedParameters(in []reflect.Value) (map[int]interface{}, error) {
+	fnType := reflect.TypeOf(b.constructor)
+	parameters := make(map[int]interface{}, len(b.specifiedParameters)+len(in))
+	for i, v := range b.specifiedParameters {
+		parameters[i] = v
+	}
+	args := make([]reflect.Type, len(in))
+	for j, arg := range in {
+		args[j] = arg.Type()
+	}
+	for j, index := range assistedIndexes(fnType, args) {
+		if index < 0 {
+			return nil, containerError(ErrInvalidFunction, "factory argument %d (%s) matches no parameter of constructor %s", j, args[j], fnType)
+		}
+		parameters[index] = in[j].Interface()
+	}
+	return parameters, nil
+}
+
+// assistedIndexes 将工厂的参数类型args依次匹配到构造函数fnType中第一个还未匹配且类型兼容的参数上，
+// 返回每个工厂参数匹配到的构造函数参数的位置，没有匹配时为-1
+func assistedIndexes(fnType reflect.Type, args []reflect.Type) []int {
+	indexes := make([]int, len(args))
+	matched := make(map[int]bool, len(args))
+	for j, arg := range args {
+		indexes[j] = -1
+		for i := 0; i < fnType.NumIn(); i++ {
+			if !matched[i] && arg.AssignableTo(fnType.In(i)) {
+				indexes[j] = i
+				matched[i] = true
+				break
+			}
+		}
+	}
+	return indexes
+}
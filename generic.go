@@ -0,0 +1,58 @@
+package iocgo
+
+// Resolver 可以获得接口对应的实例，Container和Scope都实现了该接口
+type Resolver interface {
+	Resolve(abstraction interface{}, options ...ResolveOption) error
+}
+
+func resolverOrDefault(r Resolver) Resolver {
+	if r == nil {
+		return container
+	}
+	return r
+}
+
+// ResolveAs 获得类型T对应的实例，r可以是Container或者Scope，为nil时使用全局容器。
+// 与Resolve不同，不需要传入接口的指针：
+//
+//	fb, err := iocgo.ResolveAs[Foobarer](c)
+func ResolveAs[T any](r Resolver, options ...ResolveOption) (T, error) {
+	var instance T
+	err := resolverOrDefault(r).Resolve(&instance, options...)
+	return instance, err
+}
+
+// MustResolve 与ResolveAs相同，但是获得实例失败时会panic，适合在程序启动时使用
+func MustResolve[T any](r Resolver, options ...ResolveOption) T {
+	instance, err := ResolveAs[T](r, options...)
+	if err != nil {
+		panic(err)
+	}
+	return instance
+}
+
+// RegisterInstanceOf 注册类型T对应的实例，c为nil时使用全局容器
+func RegisterInstanceOf[T any](c *Container, instance T, options ...Option) error {
+	if c == nil {
+		c = container
+	}
+	return c.RegisterInstance((*T)(nil), instance, options...)
+}
+
+// Provide 注册一个构造函数，其第一个返回值映射到类型T，相当于Register时指定Interface，c为nil时使用全局容器
+func Provide[T any](c *Container, constructor interface{}, options ...Option) error {
+	if c == nil {
+		c = container
+	}
+	return c.Register(constructor, append([]Option{Interface((*T)(nil))}, options...)...)
+}
+
+// RegisterTypeOf 注册struct类型T，生成的构造函数返回*T，相当于RegisterType((*T)(nil))，c为nil时使用全局容器：
+//
+//	iocgo.RegisterTypeOf[Foobar](c, iocgo.Interface((*Foobarer)(nil)))
+func RegisterTypeOf[T any](c *Container, options ...Option) error {
+	if c == nil {
+		c = container
+	}
+	return c.RegisterType((*T)(nil), options...)
+}
@@ -0,0 +1,177 @@
+package iocgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Graph 是容器中注册的依赖关系图，可以导出为Graphviz DOT、Mermaid或者JSON。
+// 节点、别名和边都是排序后的，相同的注册总是得到相同的输出
+type Graph struct {
+	Nodes   []GraphNode  `json:"nodes"`
+	Aliases []GraphAlias `json:"aliases"`
+	Edges   []GraphEdge  `json:"edges"`
+}
+
+// GraphNode 是图中的一个binding
+type GraphNode struct {
+	ID        string `json:"id"`             //唯一标识，格式为"类型(name=名字)"
+	Type      string `json:"type"`           //binding对应的接口类型
+	Name      string `json:"name,omitempty"` //binding的名字
+	Default   bool   `json:"default"`        //是否是该接口的默认binding
+	Lifestyle string `json:"lifestyle"`      //singleton、transient或scoped
+	Instance  bool   `json:"instance"`       //是否是通过RegisterInstance注册的实例
+}
+
+// GraphAlias 是通过RegisterSubInterface注册的子接口到接口的映射
+type GraphAlias struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// GraphEdge 是构造函数的一个参数对应的依赖
+type GraphEdge struct {
+	From      string `json:"from"`            //依赖者的节点ID
+	To        string `json:"to"`              //被依赖的节点ID，找不到时为参数的类型和名字
+	Index     int    `json:"index"`           //参数在构造函数中的位置
+	Name      string `json:"name,omitempty"`  //通过DependsOn指定的名字
+	Optional  bool   `json:"optional"`        //是否是可选参数
+	Lazy      bool   `json:"lazy"`            //是否是延迟获取的依赖
+	Decorator bool   `json:"decorator"`       //是否是装饰器的参数，如果是，Index是参数在装饰器中的位置
+	Group     string `json:"group,omitempty"` //参数依赖的分组，分组中的每个binding都有一条边
+	Missing   bool   `json:"missing"`         //是否找不到对应的binding
+}
+
+// DependencyGraph 不调用任何构造函数，获得容器中注册的依赖关系图
+func (c *Container) DependencyGraph() *Graph {
+	g := &Graph{Nodes: []GraphNode{}, Aliases: []GraphAlias{}, Edges: []GraphEdge{}}
+	for _, b := range c.allBindings() {
+		defaultBinding, _ := c.getBinding(b.abstract, "")
+		g.Nodes = append(g.Nodes, GraphNode{
+			ID:        b.String(),
+			Type:      b.abstract.String(),
+			Name:      b.name,
+			Default:   defaultBinding == b,
+			Lifestyle: b.lifestyle(),
+			Instance:  b.constructor == nil,
+		})
+		resolver := b.resolver(c)
+		for _, dep := range b.dependencies(resolver) {
+			edge := GraphEdge{From: b.String(), Index: dep.index, Name: dep.name, Optional: dep.optional, Lazy: dep.lazy,
+				Decorator: dep.decorator, Group: dep.group}
+			dbs, err := resolver.dependencyBindings(dep)
+			if err != nil {
+				edge.To = dep.describe()
+				edge.Missing = true
+				g.Edges = append(g.Edges, edge)
+			}
+			for _, db := range dbs {
+				edge.To = db.String()
+				g.Edges = append(g.Edges, edge)
+			}
+		}
+	}
+	aliases := make(map[reflect.Type]reflect.Type)
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		for from, to := range cur.alias {
+			if _, exist := aliases[from]; !exist {
+				aliases[from] = to
+			}
+		}
+		cur.mu.RUnlock()
+	}
+	for from, to := range aliases {
+		g.Aliases = append(g.Aliases, GraphAlias{From: from.String(), To: to.String()})
+	}
+	sort.Slice(g.Aliases, func(i, j int) bool { return g.Aliases[i].From < g.Aliases[j].From })
+	return g
+}
+
+func (b *binding) lifestyle() string {
+	switch {
+	case b.isScoped:
+		return "scoped"
+	case b.isTransient:
+		return "transient"
+	}
+	return "singleton"
+}
+
+// JSON 导出为JSON
+func (g *Graph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// DOT 导出为Graphviz的DOT格式，虚线表示子接口映射，点线表示可选或者延迟获取的依赖，红色表示找不到的依赖
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph iocgo {\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "  %q [label=%q];\n", n.ID, n.ID+"\n"+n.describe())
+	}
+	for _, a := range g.Aliases {
+		fmt.Fprintf(&sb, "  %q -> %q [style=dashed, label=\"alias\"];\n", a.From, a.To)
+	}
+	for _, e := range g.Edges {
+		attrs := []string{fmt.Sprintf("label=\"%d\"", e.Index)}
+		if e.Optional || e.Lazy {
+			attrs = append(attrs, "style=dotted")
+		}
+		if e.Missing {
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(&sb, "  %q -> %q [%s];\n", e.From, e.To, strings.Join(attrs, ", "))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid 导出为Mermaid的flowchart格式
+func (g *Graph) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	ids := make(map[string]string)
+	//找不到的依赖和子接口没有对应的binding节点，需要单独声明
+	node := func(id, label string) string {
+		if key, ok := ids[id]; ok {
+			return key
+		}
+		key := fmt.Sprintf("n%d", len(ids))
+		ids[id] = key
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", key, mermaidEscape(label))
+		return key
+	}
+	for _, n := range g.Nodes {
+		node(n.ID, n.ID+"<br/>"+n.describe())
+	}
+	for _, a := range g.Aliases {
+		fmt.Fprintf(&sb, "  %s -.->|alias| %s\n", node(a.From, a.From), node(a.To, a.To))
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Optional || e.Lazy {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&sb, "  %s %s|%d| %s\n", node(e.From, e.From), arrow, e.Index, node(e.To, e.To))
+	}
+	return sb.String()
+}
+
+func (n GraphNode) describe() string {
+	desc := n.Lifestyle
+	if n.Default {
+		desc += ", default"
+	}
+	if n.Instance {
+		desc += ", instance"
+	}
+	return desc
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;").Replace(s)
+}
@@ -0,0 +1,119 @@
+package iocgo
+
+import (
+	"reflect"
+	"sort"
+	"sync/atomic"
+)
+
+// 通过Group注册的binding属于一个或多个分组，同一个分组中的binding可以来自不同的包，类型也可以不同。
+// 构造函数通过DependsOnGroup指定某个切片类型的参数依赖一个分组，Fill通过`group:"分组名"`标签指定，
+// 容器会按Order指定的顺序以及注册的顺序获得分组中所有可以赋值给切片元素类型的实例，分组为空时得到空切片。
+// 同一个接口的binding即使因为名字相同被覆盖，也仍然保留在分组中
+
+// addToGroups 将binding加入其所属的分组，调用者需要持有c.mu
+func (c *Container) addToGroups(b *binding) {
+	for _, group := range b.groups {
+		c.groups[group] = append(c.groups[group], b)
+	}
+}
+
+// groupMembers 获得分组中类型可以赋值给elem的binding，按Order以及注册的顺序排列
+func (c *Container) groupMembers(group string, elem reflect.Type) []*binding {
+	var candidates, members []*binding
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		candidates = append(candidates, cur.groups[group]...)
+		cur.mu.RUnlock()
+	}
+	//判断条件时不能持有容器的锁
+	for _, b := range candidates {
+		if b.abstract.AssignableTo(elem) && b.isActive() {
+			members = append(members, b)
+		}
+	}
+	sortByOrder(members)
+	return members
+}
+
+// namedBindings 获得某个接口在本容器以及所有父容器中的binding，同名的binding以子容器中的为准，
+// 同一个容器中参与解析的带有条件的binding优先
+func (c *Container) namedBindings(theType reflect.Type) map[string]*binding {
+	bindings := make(map[string]*binding)
+	for cur := c; cur != nil; cur = cur.parent {
+		for _, b := range cur.activeConditionals(theType) {
+			if _, exist := bindings[b.name]; !exist {
+				bindings[b.name] = b
+			}
+		}
+		cur.mu.RLock()
+		if nb, ok := cur.bind[theType]; ok {
+			for name, b := range nb.namedBinding {
+				if _, exist := bindings[name]; !exist {
+					bindings[name] = b
+				}
+			}
+		}
+		cur.mu.RUnlock()
+	}
+	return bindings
+}
+
+// sortedBindings 获得某个接口的所有binding，按Order以及注册的顺序排列
+func (c *Container) sortedBindings(elem reflect.Type) []*binding {
+	named := c.namedBindings(elem)
+	bindings := make([]*binding, 0, len(named))
+	for _, b := range named {
+		bindings = append(bindings, b)
+	}
+	sortByOrder(bindings)
+	return bindings
+}
+
+// registrations 是所有容器共享的注册计数，用于记录binding注册以及单例创建的先后顺序
+var registrations uint64
+
+func nextRegistration() uint64 {
+	return atomic.AddUint64(&registrations, 1)
+}
+
+// sortByOrder 将bindings按Order从小到大排列，Order相同的按注册的顺序排列
+func sortByOrder(bindings []*binding) {
+	sort.Slice(bindings, func(i, j int) bool {
+		if bindings[i].order != bindings[j].order {
+			return bindings[i].order < bindings[j].order
+		}
+		return bindings[i].seq < bindings[j].seq
+	})
+}
+
+// sliceValue 获得bindings中所有的实例，组成类型为sliceType的切片
+func (c *Container) sliceValue(r *resolution, sliceType reflect.Type, bindings []*binding) (reflect.Value, error) {
+	slice := reflect.MakeSlice(sliceType, 0, len(bindings))
+	for _, b := range bindings {
+		instance, err := b.resolve(c, r)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v := reflect.Zero(sliceType.Elem())
+		if instance != nil {
+			v = reflect.ValueOf(instance)
+		}
+		slice = reflect.Append(slice, v)
+	}
+	return slice, nil
+}
+
+// groupValue 获得分组中所有的实例，组成类型为sliceType的切片
+func (c *Container) groupValue(r *resolution, sliceType reflect.Type, group string) (reflect.Value, error) {
+	if sliceType.Kind() != reflect.Slice {
+		return reflect.Value{}, newResolutionError(r.path, "group "+group,
+			containerError(ErrInvalidConstructor, "group %s must be injected into a slice, not %s", group, sliceType))
+	}
+	return c.sliceValue(r, sliceType, c.groupMembers(group, sliceType.Elem()))
+}
+
+// isInterfaceSlice 判断t是不是接口的切片，这样的参数或字段在没有对应的binding时会得到该接口所有的实例
+func isInterfaceSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Interface
+}
@@ -0,0 +1,139 @@
+// Package iocgotest 提供在测试中使用iocgo容器的辅助函数：临时替换binding、保存和恢复全局容器，
+// 以及检查测试过程中获得过哪些binding。例如：
+//
+//	func TestHandler(t *testing.T) {
+//	    c := iocgotest.Isolate(t) //全局容器的副本，可以在并行的测试中使用
+//	    iocgotest.Override(t, c, (*Store)(nil), &fakeStore{})
+//	    rec := iocgotest.Record(t, c)
+//	    ...
+//	    rec.AssertResolved(t, (*Store)(nil))
+//	}
+package iocgotest
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/studyzy/iocgo"
+)
+
+// Override 在测试期间用fake替换容器c中interfacePtr对应接口的binding，测试结束时通过t.Cleanup恢复，
+// c为nil时替换全局容器中的binding，可以通过iocgo.Name指定替换的binding的名字
+func Override(t testing.TB, c *iocgo.Container, interfacePtr interface{}, fake interface{}, options ...iocgo.Option) {
+	t.Helper()
+	var restore func()
+	var err error
+	if c == nil {
+		restore, err = iocgo.Override(interfacePtr, fake, options...)
+	} else {
+		restore, err = c.Override(interfacePtr, fake, options...)
+	}
+	if err != nil {
+		t.Fatalf("iocgotest: override %T: %v", interfacePtr, err)
+	}
+	t.Cleanup(restore)
+}
+
+// Snapshot 保存全局容器当前的状态，测试结束时通过t.Cleanup恢复，这样测试中对全局容器的注册不会影响其他测试。
+// 返回的快照也可以通过Restore在测试中提前恢复
+func Snapshot(t testing.TB) *iocgo.Container {
+	snapshot := iocgo.Snapshot()
+	t.Cleanup(func() { iocgo.Restore(snapshot) })
+	return snapshot
+}
+
+// Restore 将全局容器恢复为snapshot的状态
+func Restore(snapshot *iocgo.Container) {
+	iocgo.Restore(snapshot)
+}
+
+// Isolate 获得全局容器的一个副本，对副本的注册和替换不会影响全局容器，可以在并行的测试中使用，测试结束时副本会被关闭
+func Isolate(t testing.TB) *iocgo.Container {
+	c := iocgo.Snapshot()
+	t.Cleanup(func() { _ = c.Close(context.Background()) })
+	return c
+}
+
+// Recorder 记录测试期间通过容器获得过的binding
+type Recorder struct {
+	mu       sync.Mutex
+	resolved map[key]int
+}
+
+type key struct {
+	t    reflect.Type
+	name string
+}
+
+// Record 开始记录通过容器c以及其子容器获得的binding，测试结束时停止记录，c为nil时记录全局容器
+func Record(t testing.TB, c *iocgo.Container) *Recorder {
+	rec := &Recorder{resolved: make(map[key]int)}
+	observe := iocgo.Observe
+	if c != nil {
+		observe = c.Observe
+	}
+	t.Cleanup(observe(func(t reflect.Type, name string) {
+		rec.mu.Lock()
+		rec.resolved[key{t, name}]++
+		rec.mu.Unlock()
+	}))
+	return rec
+}
+
+// Count 获得interfacePtr对应接口名为name的binding被获得的次数，没有指定name时为默认名字""
+func (r *Recorder) Count(interfacePtr interface{}, name ...string) int {
+	k := key{t: reflect.TypeOf(interfacePtr).Elem()}
+	if len(name) > 0 {
+		k.name = name[0]
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.resolved[k]
+}
+
+// Resolved 获得所有被获得过的binding的描述，按字母顺序排列
+func (r *Recorder) Resolved() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	resolved := make([]string, 0, len(r.resolved))
+	for k := range r.resolved {
+		s := k.t.String()
+		if k.name != "" {
+			s += "(name=" + k.name + ")"
+		}
+		resolved = append(resolved, s)
+	}
+	sort.Strings(resolved)
+	return resolved
+}
+
+// AssertResolved 检查interfacePtr对应接口名为name的binding被获得过
+func (r *Recorder) AssertResolved(t testing.TB, interfacePtr interface{}, name ...string) bool {
+	t.Helper()
+	if r.Count(interfacePtr, name...) == 0 {
+		t.Errorf("iocgotest: %s was not resolved, resolved: %v", describe(interfacePtr, name), r.Resolved())
+		return false
+	}
+	return true
+}
+
+// AssertNotResolved 检查interfacePtr对应接口名为name的binding没有被获得过
+func (r *Recorder) AssertNotResolved(t testing.TB, interfacePtr interface{}, name ...string) bool {
+	t.Helper()
+	if n := r.Count(interfacePtr, name...); n > 0 {
+		t.Errorf("iocgotest: %s was resolved %d times", describe(interfacePtr, name), n)
+		return false
+	}
+	return true
+}
+
+func describe(interfacePtr interface{}, name []string) string {
+	s := reflect.TypeOf(interfacePtr).Elem().String()
+	if len(name) > 0 && name[0] != "" {
+		s += "(name=" + name[0] + ")"
+	}
+	return s
+}
@@ -0,0 +1,105 @@
+package iocgotest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/studyzy/iocgo"
+	"github.com/studyzy/iocgo/iocgotest"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type greeter string
+
+func (g greeter) Greet() string {
+	return string(g)
+}
+
+type Service struct {
+	Greeter Greeter
+}
+
+func TestOverride(t *testing.T) {
+	c := iocgo.NewContainer()
+	c.Register(func() Greeter { return greeter("real") })
+	c.Register(func() Greeter { return greeter("named") }, iocgo.Name("named"))
+	t.Run("override", func(t *testing.T) {
+		iocgotest.Override(t, c, (*Greeter)(nil), greeter("fake"))
+		iocgotest.Override(t, c, (*Greeter)(nil), greeter("fake named"), iocgo.Name("named"))
+		g, err := iocgo.ResolveAs[Greeter](c)
+		assert.Nil(t, err)
+		assert.Equal(t, "fake", g.Greet())
+		g, err = iocgo.ResolveAs[Greeter](c, iocgo.ResolveName("named"))
+		assert.Nil(t, err)
+		assert.Equal(t, "fake named", g.Greet())
+	})
+	g, err := iocgo.ResolveAs[Greeter](c)
+	assert.Nil(t, err)
+	assert.Equal(t, "real", g.Greet())
+	g, err = iocgo.ResolveAs[Greeter](c, iocgo.ResolveName("named"))
+	assert.Nil(t, err)
+	assert.Equal(t, "named", g.Greet())
+}
+
+func TestSnapshot(t *testing.T) {
+	defer iocgo.Reset()
+	iocgo.Register(func() Greeter { return greeter("global") })
+	t.Run("snapshot", func(t *testing.T) {
+		iocgotest.Snapshot(t)
+		iocgo.Reset()
+		iocgo.Register(func() Greeter { return greeter("test") })
+		iocgotest.Override(t, nil, (*Greeter)(nil), greeter("fake"))
+		g, err := iocgo.ResolveAs[Greeter](nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "fake", g.Greet())
+	})
+	g, err := iocgo.ResolveAs[Greeter](nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "global", g.Greet())
+
+	snapshot := iocgo.Snapshot()
+	iocgo.Reset()
+	iocgotest.Restore(snapshot)
+	g, err = iocgo.ResolveAs[Greeter](nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "global", g.Greet())
+}
+
+func TestIsolate(t *testing.T) {
+	//并行的子测试在本函数返回之后才执行，需要在所有子测试结束之后再清空全局容器
+	t.Cleanup(iocgo.Reset)
+	iocgo.Register(func() Greeter { return greeter("global") })
+	iocgo.Register(func() Greeter { return greeter("global only") }, iocgo.Name("global"))
+	for _, name := range []string{"a", "b"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c := iocgotest.Isolate(t)
+			iocgotest.Override(t, c, (*Greeter)(nil), greeter(name))
+			g, err := iocgo.ResolveAs[Greeter](c)
+			assert.Nil(t, err)
+			assert.Equal(t, name, g.Greet())
+			g, err = iocgo.ResolveAs[Greeter](c, iocgo.ResolveName("global"))
+			assert.Nil(t, err)
+			assert.Equal(t, "global only", g.Greet())
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	c := iocgo.NewContainer()
+	c.Register(func() Greeter { return greeter("real") })
+	c.Register(func() Greeter { return greeter("unused") }, iocgo.Name("unused"))
+	rec := iocgotest.Record(t, c)
+	s := &Service{}
+	assert.Nil(t, c.Fill(s))
+	_, err := iocgo.ResolveAs[Greeter](c.NewChild())
+	assert.Nil(t, err)
+	rec.AssertResolved(t, (*Greeter)(nil))
+	rec.AssertNotResolved(t, (*Greeter)(nil), "unused")
+	assert.Equal(t, 2, rec.Count((*Greeter)(nil)))
+	assert.Equal(t, []string{"iocgotest_test.Greeter"}, rec.Resolved())
+}
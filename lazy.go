@@ -0,0 +1,175 @@
+package iocgo
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Lazy 是一个延迟获取的依赖。构造函数声明Lazy[T]类型的参数时，容器注入的只是一个句柄，
+// 直到第一次调用Get时才会获得T对应的实例，之后的调用返回同一个实例。
+// 构造函数也可以声明func() (T, error)或func() T类型的参数，每次调用都会从容器获得实例，
+// 带参数的函数类型则是创建新实例的工厂，参见factory.go。
+// 延迟获取的依赖不会在构造时被解析，因此可以用来打破构造函数之间的循环依赖
+type Lazy[T any] struct {
+	state *lazyState
+}
+
+// Get 获得T对应的实例，只有第一次调用时才会从容器中获取
+func (l Lazy[T]) Get() (T, error) {
+	var instance T
+	if l.state == nil {
+		return instance, containerError(ErrNotFound, "lazy %s is not injected by container", l.lazyTarget())
+	}
+	inst, err := l.state.get()
+	if err != nil || inst == nil {
+		return instance, err
+	}
+	return inst.(T), nil
+}
+
+// MustGet 与Get相同，但是获得实例失败时会panic
+func (l Lazy[T]) MustGet() T {
+	instance, err := l.Get()
+	if err != nil {
+		panic(err)
+	}
+	return instance
+}
+
+func (Lazy[T]) lazyTarget() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (Lazy[T]) withState(state *lazyState) interface{} {
+	return Lazy[T]{state: state}
+}
+
+// lazyDependency 由所有的Lazy[T]实现，用于识别构造函数中的Lazy参数
+type lazyDependency interface {
+	lazyTarget() reflect.Type
+	withState(state *lazyState) interface{}
+}
+
+// lazyState 是Lazy句柄共享的状态，保证实例只被获取一次
+type lazyState struct {
+	mu       sync.Mutex
+	done     bool
+	instance interface{}
+	resolve  func() (interface{}, error)
+}
+
+func (s *lazyState) get() (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return s.instance, nil
+	}
+	inst, err := s.resolve()
+	if err != nil {
+		return nil, err
+	}
+	s.instance, s.done = inst, true
+	return inst, nil
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// lazyTarget 判断参数类型t是不是延迟获取的依赖或者工厂，如果是，返回真正依赖的类型。
+// 已经在容器中注册了的函数类型按普通依赖处理
+func (c *Container) lazyTarget(t reflect.Type) (reflect.Type, bool) {
+	if l, ok := reflect.Zero(t).Interface().(lazyDependency); ok {
+		return l.lazyTarget(), true
+	}
+	if t.Kind() != reflect.Func || t.IsVariadic() || t.NumOut() == 0 || t.NumOut() > 2 ||
+		(t.NumOut() == 2 && t.Out(1) != errorType) {
+		return nil, false
+	}
+	if _, err := c.getBinding(t, ""); err == nil {
+		return nil, false
+	}
+	return t.Out(0), true
+}
+
+// lazyValue 为延迟获取的参数类型t创建注入的值，target是真正依赖的类型，name是依赖的binding的名字
+func (c *Container) lazyValue(r *resolution, t reflect.Type, target reflect.Type, name string) reflect.Value {
+	resolve := func() (interface{}, error) {
+		return c.resolveDeferred(r, target, name)
+	}
+	if l, ok := reflect.Zero(t).Interface().(lazyDependency); ok {
+		return reflect.ValueOf(l.withState(&lazyState{resolve: resolve}))
+	}
+	if t.NumIn() > 0 {
+		return c.factoryValue(r, t, target, name)
+	}
+	return reflect.MakeFunc(t, func([]reflect.Value) []reflect.Value {
+		inst, err := resolve()
+		return funcResults(t, target, inst, err)
+	})
+}
+
+// funcResults 将获得的实例和错误转换为函数类型t的返回值，t只返回实例时，获得实例失败会panic
+func funcResults(t reflect.Type, target reflect.Type, inst interface{}, err error) []reflect.Value {
+	result := reflect.Zero(target)
+	if inst != nil {
+		result = reflect.ValueOf(inst)
+	}
+	if t.NumOut() == 1 {
+		if err != nil { //func() T无法返回error
+			panic(err)
+		}
+		return []reflect.Value{result}
+	}
+	errValue := reflect.Zero(errorType)
+	if err != nil {
+		errValue = reflect.ValueOf(&err).Elem()
+	}
+	return []reflect.Value{result, errValue}
+}
+
+// mayFail 判断获得binding的实例时，构造函数或者装饰器是否可能返回error
+func (b *binding) mayFail() bool {
+	functions := []interface{}{b.constructor}
+	for _, d := range b.decorators() {
+		functions = append(functions, d.constructor)
+	}
+	for _, fn := range functions {
+		if fn == nil {
+			continue
+		}
+		t := reflect.TypeOf(fn)
+		for i := 0; i < t.NumOut(); i++ {
+			if t.Out(i) == errorType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveDeferred 获得延迟依赖的实例。如果依赖者仍在构造中，沿用其解析链以检测循环依赖，
+// 否则依赖者已经构造完成，使用新的解析链
+func (c *Container) resolveDeferred(r *resolution, target reflect.Type, name string) (interface{}, error) {
+	if r.isFinished() {
+		r = &resolution{scope: r.scope}
+	}
+	b, err := c.getBinding(target, name)
+	if err != nil {
+		err = newResolutionError(r.path, describe(target, name), err)
+	} else {
+		var inst interface{}
+		if inst, err = b.resolve(c, r); err == nil {
+			return inst, nil
+		}
+	}
+	return nil, withRequest(err, target, name)
+}
+
+// finish 标记解析链上最后的binding已经构造完成
+func (r *resolution) finish() {
+	atomic.StoreInt32(&r.finished, 1)
+}
+
+func (r *resolution) isFinished() bool {
+	return atomic.LoadInt32(&r.finished) == 1
+}
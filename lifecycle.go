@@ -0,0 +1,253 @@
+package iocgo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Hook 是容器Start或Stop时对某个实例执行的生命周期钩子
+type Hook func(ctx context.Context, instance interface{}) error
+
+// Disposer 由需要在容器关闭时释放资源的实例实现，优先于io.Closer被调用
+type Disposer interface {
+	Dispose(ctx context.Context) error
+}
+
+// MultiError 聚合了多个错误，比如Close时多个实例释放失败
+type MultiError []error
+
+func (m MultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap 返回所有被聚合的错误
+func (m MultiError) Unwrap() []error {
+	return m
+}
+
+// Is 使errors.Is可以匹配被聚合的任意一个错误，Go 1.20之前errors.Is不会遍历Unwrap() []error
+func (m MultiError) Is(target error) bool {
+	for _, err := range m {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As 使errors.As可以匹配被聚合的任意一个错误，按聚合的顺序取第一个匹配的错误
+func (m MultiError) As(target interface{}) bool {
+	for _, err := range m {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// newMultiError 聚合多个错误，只有一个错误时直接返回该错误
+func newMultiError(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return MultiError(errs)
+}
+
+// resolvedInstance 记录一个由容器持有的实例以及产生它的binding
+type resolvedInstance struct {
+	binding  *binding
+	instance interface{}
+	seq      uint64 //创建的顺序，与binding注册的顺序共享计数
+}
+
+// track 记录一个新创建或注册的实例，用于Close时按逆序释放
+func (c *Container) track(b *binding, instance interface{}) {
+	if c.tracker != nil {
+		c.tracker.track(b, instance)
+		return
+	}
+	c.lifeMu.Lock()
+	c.resolved = append(c.resolved, resolvedInstance{binding: b, instance: instance, seq: nextRegistration()})
+	c.lifeMu.Unlock()
+}
+
+// Close 按实例创建顺序的逆序释放容器持有的所有单例，这样依赖者总是先于其依赖被释放。
+// 实现了Disposer的实例调用Dispose，实现了io.Closer的实例调用Close，
+// 通过ExternallyOwned注册的实例会被跳过。释放过程中的多个错误会被聚合为MultiError返回
+func (c *Container) Close(ctx context.Context) error {
+	c.lifeMu.Lock()
+	resolved := c.resolved
+	c.resolved = nil
+	c.started = nil
+	c.nStarted = 0
+	c.lifeMu.Unlock()
+	c.mu.Lock()
+	c.overridden = make(map[*binding]*instanceSlot)
+	c.overriddenCalls = make(map[*sharedCall]*sharedCall)
+	c.mu.Unlock()
+	for _, r := range resolved {
+		if (r.binding.owner == c || r.binding.owner.tracker == c) && r.binding.constructor != nil {
+			r.binding.reset() //释放后再次Resolve会重新构造
+		}
+	}
+	return disposeAll(ctx, resolved)
+}
+
+// reset 丢弃单例已经构造的实例，之后再获得时重新构造
+func (b *binding) reset() {
+	b.mu.Lock()
+	b.instance = nil
+	b.mu.Unlock()
+	if b.call != nil {
+		b.call.reset()
+	}
+}
+
+// disposeAll 按逆序释放resolved中的实例
+func disposeAll(ctx context.Context, resolved []resolvedInstance) error {
+	var errs []error
+	disposed := make(map[interface{}]bool)
+	for i := len(resolved) - 1; i >= 0; i-- {
+		r := resolved[i]
+		if r.binding.externallyOwned || r.instance == nil {
+			continue
+		}
+		if reflect.TypeOf(r.instance).Kind() == reflect.Ptr { //同一个对象可能被绑定到多个接口，只释放一次
+			if disposed[r.instance] {
+				continue
+			}
+			disposed[r.instance] = true
+		}
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := dispose(ctx, r.instance); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return newMultiError(errs)
+}
+
+func dispose(ctx context.Context, instance interface{}) error {
+	switch inst := instance.(type) {
+	case Disposer:
+		return inst.Dispose(ctx)
+	case io.Closer:
+		return inst.Close()
+	}
+	return nil
+}
+
+// Start 按依赖顺序执行所有实例的OnStart钩子，依赖总是先于依赖者启动。
+// 注册了钩子但尚未被构造的单例会先被Resolve出来。
+// 某个钩子失败时，本次已经启动的实例会按逆序执行OnStop钩子进行回滚
+func (c *Container) Start(ctx context.Context) error {
+	c.mu.RLock()
+	containers := []*Container{c}
+	for _, private := range c.modules { //模块中的实例由安装模块的容器启动
+		containers = append(containers, private)
+	}
+	c.mu.RUnlock()
+	for _, cur := range containers {
+		for _, b := range cur.hookedBindings() {
+			if _, err := b.resolve(cur, &resolution{ctx: ctx}); err != nil {
+				return err
+			}
+		}
+	}
+	//实例按创建顺序记录，被依赖的实例总是先于依赖者创建完成
+	c.lifeMu.Lock()
+	from, to := c.nStarted, len(c.resolved)
+	pending := c.resolved[from:to]
+	c.nStarted = to
+	c.lifeMu.Unlock()
+	started := make([]resolvedInstance, 0, len(pending))
+	for _, r := range pending {
+		if err := runHooks(ctx, r.binding.onStart, r.instance); err != nil {
+			//回滚后这些实例都没有启动，再次Start时需要重新处理
+			c.lifeMu.Lock()
+			if c.nStarted == to {
+				c.nStarted = from
+			}
+			c.lifeMu.Unlock()
+			if stopErr := stopAll(ctx, started); stopErr != nil {
+				return MultiError{err, stopErr}
+			}
+			return err
+		}
+		started = append(started, r)
+	}
+	c.lifeMu.Lock()
+	c.started = append(c.started, started...)
+	c.lifeMu.Unlock()
+	return nil
+}
+
+// hookedBindings 获得本容器中注册了钩子的单例binding，带有条件的binding只有参与解析时才包含在内
+func (c *Container) hookedBindings() []*binding {
+	var candidates, hooked []*binding
+	var conditional []reflect.Type
+	c.mu.RLock()
+	for _, nb := range c.bind {
+		for _, b := range nb.namedBinding {
+			candidates = append(candidates, b)
+		}
+	}
+	for t := range c.conditional {
+		conditional = append(conditional, t)
+	}
+	c.mu.RUnlock()
+	for _, t := range conditional {
+		candidates = append(candidates, c.activeConditionals(t)...)
+	}
+	for _, b := range candidates {
+		if !b.isTransient && !b.isScoped && (len(b.onStart) > 0 || len(b.onStop) > 0) {
+			hooked = append(hooked, b)
+		}
+	}
+	return hooked
+}
+
+// Stop 按依赖的逆序执行所有已启动实例的OnStop钩子，依赖者总是先于其依赖停止。
+// 多个钩子的错误会被聚合为MultiError返回
+func (c *Container) Stop(ctx context.Context) error {
+	c.lifeMu.Lock()
+	started := c.started
+	c.started = nil
+	c.nStarted = 0
+	c.lifeMu.Unlock()
+	return stopAll(ctx, started)
+}
+
+func stopAll(ctx context.Context, started []resolvedInstance) error {
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		if err := runHooks(ctx, started[i].binding.onStop, started[i].instance); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return newMultiError(errs)
+}
+
+func runHooks(ctx context.Context, hooks []Hook, instance interface{}) error {
+	for _, hook := range hooks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := hook(ctx, instance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package iocgo
+
+import (
+	"reflect"
+)
+
+// 构造函数的参数以及Fill的字段是map[string]接口类型，并且容器中没有注册这个map类型时，
+// 会得到该接口的所有binding的实例，key是通过Name指定的名字，没有指定名字的binding的key为""。
+// 子容器中的binding会覆盖父容器中同名的binding
+
+// isInterfaceMap 判断t是不是以名字为key的接口map
+func isInterfaceMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.Interface
+}
+
+// mapValue 获得bindings中所有的实例，以binding的名字为key组成类型为mapType的map
+func (c *Container) mapValue(r *resolution, mapType reflect.Type, bindings []*binding) (reflect.Value, error) {
+	m := reflect.MakeMapWithSize(mapType, len(bindings))
+	for _, b := range bindings {
+		instance, err := b.resolve(c, r)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v := reflect.Zero(mapType.Elem())
+		if instance != nil {
+			v = reflect.ValueOf(instance)
+		}
+		m.SetMapIndex(reflect.ValueOf(b.name).Convert(mapType.Key()), v)
+	}
+	return m, nil
+}
+
+// collectionValue 为接口的切片或者以名字为key的接口map获得该接口所有的实例，
+// t不是这样的类型或者该接口没有任何binding时ok为false
+func (c *Container) collectionValue(r *resolution, t reflect.Type) (v reflect.Value, ok bool, err error) {
+	if !isInterfaceSlice(t) && !isInterfaceMap(t) {
+		return reflect.Value{}, false, nil
+	}
+	bindings := c.sortedBindings(t.Elem())
+	if len(bindings) == 0 {
+		return reflect.Value{}, false, nil
+	}
+	if t.Kind() == reflect.Map {
+		v, err = c.mapValue(r, t, bindings)
+	} else {
+		v, err = c.sliceValue(r, t, bindings)
+	}
+	return v, err == nil, err
+}
@@ -0,0 +1,194 @@
+package iocgo
+
+import (
+	"reflect"
+)
+
+// Module 将一组相关的注册打包在一起，通过Container.Install安装到容器中。
+// 模块中的binding注册在安装时创建的私有子容器里，只有通过Export导出的接口对安装模块的容器可见，
+// 其他binding只能被模块内部的构造函数依赖。模块内部的构造函数可以依赖安装模块的容器中的binding，
+// 同一个名字的模块在容器及其父容器中只能安装一次。例如：
+//
+//	var StorageModule = iocgo.NewModule("storage").
+//	    Register(NewConnectionPool).
+//	    Register(NewRepository).
+//	    Export((*Repository)(nil))
+//	err := container.Install(StorageModule)
+type Module struct {
+	name     string
+	requires []string
+	exports  []interface{}
+	installs []func(c *Container) error
+}
+
+// NewModule 创建一个名为name的模块
+func NewModule(name string) *Module {
+	return &Module{name: name}
+}
+
+// Name 获得模块的名字
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Register 在模块中注册一个构造函数，与Container.Register相同
+func (m *Module) Register(constructor interface{}, options ...Option) *Module {
+	return m.add(func(c *Container) error {
+		return c.Register(constructor, options...)
+	})
+}
+
+// RegisterInstance 在模块中注册一个实例，与Container.RegisterInstance相同
+func (m *Module) RegisterInstance(interfacePtr interface{}, instance interface{}, options ...Option) *Module {
+	return m.add(func(c *Container) error {
+		return c.RegisterInstance(interfacePtr, instance, options...)
+	})
+}
+
+// RegisterSubInterface 在模块中注册一个子接口，与Container.RegisterSubInterface相同
+func (m *Module) RegisterSubInterface(subInterfacePtr interface{}, interfacePtr interface{}) *Module {
+	return m.add(func(c *Container) error {
+		return c.RegisterSubInterface(subInterfacePtr, interfacePtr)
+	})
+}
+
+// SetDefaultBinding 设置模块中某个接口的默认binding，与Container.SetDefaultBinding相同
+func (m *Module) SetDefaultBinding(interfacePtr interface{}, defaultName string) *Module {
+	return m.add(func(c *Container) error {
+		return c.SetDefaultBinding(interfacePtr, defaultName)
+	})
+}
+
+// Decorate 在模块中注册一个装饰器，只对模块中的binding生效
+func (m *Module) Decorate(decorator interface{}, options ...Option) *Module {
+	return m.add(func(c *Container) error {
+		return c.Decorate(decorator, options...)
+	})
+}
+
+// Requires 声明模块依赖的其他模块，安装时这些模块必须已经安装在容器或其父容器中
+func (m *Module) Requires(modules ...string) *Module {
+	m.requires = append(m.requires, modules...)
+	return m
+}
+
+// Export 导出模块中的接口，这些接口的所有binding都会加入安装模块的容器，
+// 导出的binding仍然在模块的私有容器中构造，可以依赖模块中未导出的binding
+func (m *Module) Export(interfacePtrs ...interface{}) *Module {
+	m.exports = append(m.exports, interfacePtrs...)
+	return m
+}
+
+func (m *Module) add(install func(c *Container) error) *Module {
+	m.installs = append(m.installs, install)
+	return m
+}
+
+// Install 按顺序将模块安装到容器中，模块重复安装或者依赖的模块没有安装时返回错误，
+// 某个模块安装失败时，它的注册都不会生效，之前的模块仍然保持安装
+func (c *Container) Install(modules ...*Module) error {
+	for _, m := range modules {
+		if err := c.install(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Container) install(m *Module) error {
+	if c.module(m.name) != nil {
+		return containerError(ErrDuplicateModule, "module %s", m.name)
+	}
+	for _, name := range m.requires {
+		if c.module(name) == nil {
+			return containerError(ErrNotFound, "module %s requires module %s", m.name, name)
+		}
+	}
+	private := c.NewChild()
+	private.tracker = c
+	for _, install := range m.installs {
+		if err := install(private); err != nil {
+			return err
+		}
+	}
+	exports := make([]reflect.Type, 0, len(m.exports))
+	for _, ptr := range m.exports {
+		t, err := getTypeFromInterface(ptr)
+		if err != nil {
+			return err
+		}
+		private.mu.RLock()
+		_, ok := private.bind[t]
+		ok = ok || len(private.conditional[t]) > 0
+		private.mu.RUnlock()
+		if !ok {
+			return containerError(ErrNotFound, "module %s exports %s without binding", m.name, t)
+		}
+		exports = append(exports, t)
+	}
+	private.mu.Lock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer private.mu.Unlock()
+	if c.modules[m.name] != nil { //并发安装同名模块
+		return containerError(ErrDuplicateModule, "module %s", m.name)
+	}
+	for _, t := range exports {
+		c.export(private, t)
+	}
+	c.modules[m.name] = private
+	return nil
+}
+
+// export 将模块私有容器中接口t的binding加入本容器，调用者需要持有两个容器的c.mu
+func (c *Container) export(private *Container, t reflect.Type) {
+	if nb, ok := private.bind[t]; ok {
+		for _, b := range nb.namedBinding {
+			b.exported = true
+		}
+		if exist, has := c.bind[t]; has {
+			for _, b := range nb.namedBinding {
+				exist.addNewBinding(b, b.isDefault)
+			}
+		} else {
+			c.bind[t] = nb.copy() //保留模块中设置的默认binding
+		}
+	}
+	for _, b := range private.conditional[t] {
+		b.exported = true
+		c.conditional[t] = append(c.conditional[t], b)
+	}
+	//导出的binding所属的分组移到本容器中，这样本容器和模块中都能获得它们
+	for group, members := range private.groups {
+		kept := members[:0:0]
+		for _, b := range members {
+			if b.exported && b.abstract == t {
+				c.groups[group] = append(c.groups[group], b)
+			} else {
+				kept = append(kept, b)
+			}
+		}
+		private.groups[group] = kept
+	}
+}
+
+// module 获得本容器或父容器中安装的模块的私有容器，没有安装时返回nil
+func (c *Container) module(name string) *Container {
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		private := cur.modules[name]
+		cur.mu.RUnlock()
+		if private != nil {
+			return private
+		}
+	}
+	return nil
+}
+
+// resolver 获得解析binding的依赖时使用的容器，模块导出的binding总是在模块的私有容器中解析依赖
+func (b *binding) resolver(c *Container) *Container {
+	if b.exported {
+		return b.owner
+	}
+	return c
+}
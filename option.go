@@ -1,9 +1,6 @@
-
-
 package iocgo
 
 import (
-	"errors"
 	"reflect"
 )
 
@@ -40,7 +37,7 @@ func Interface(it ...interface{}) Option {
 			}
 			ptr := reflect.TypeOf(i)
 			if ptr == nil || ptr.Kind() != reflect.Ptr {
-				return errors.New("interface input must be a interface point")
+				return containerError(ErrInvalidAbstraction, "interface input must be a interface point")
 			}
 			t := ptr.Elem()
 			b.resolveTypes = append(b.resolveTypes, t)
@@ -57,6 +54,14 @@ func Lifestyle(isTransient bool) Option {
 	}
 }
 
+//Scoped 指定在获得接口对应的实例时，在每个Scope中只构造一次，Scope关闭时释放
+func Scoped() Option {
+	return func(b *binding) error {
+		b.isScoped = true
+		return nil
+	}
+}
+
 //DependsOn 指定这个构造函数依赖的接口对应的name
 func DependsOn(dependsOn map[int]string) Option {
 	return func(b *binding) error {
@@ -81,11 +86,80 @@ func Default() Option {
 	}
 }
 
+//ExternallyOwned 指定该实例由外部管理生命周期，容器Close时不会调用其Close或Dispose方法
+func ExternallyOwned() Option {
+	return func(b *binding) error {
+		b.externallyOwned = true
+		return nil
+	}
+}
+
+//OnStart 指定容器Start时对该实例执行的钩子，可以指定多次，按顺序执行
+func OnStart(hook Hook) Option {
+	return func(b *binding) error {
+		if hook == nil {
+			return containerError(ErrInvalidFunction, "OnStart hook must not be nil")
+		}
+		b.onStart = append(b.onStart, hook)
+		return nil
+	}
+}
+
+//OnStop 指定容器Stop时对该实例执行的钩子，可以指定多次，按顺序执行
+func OnStop(hook Hook) Option {
+	return func(b *binding) error {
+		if hook == nil {
+			return containerError(ErrInvalidFunction, "OnStop hook must not be nil")
+		}
+		b.onStop = append(b.onStop, hook)
+		return nil
+	}
+}
+
+//Group 指定binding所属的分组，可以属于多个分组
+func Group(groups ...string) Option {
+	return func(b *binding) error {
+		b.groups = append(b.groups, groups...)
+		return nil
+	}
+}
+
+//Order 指定注入切片、分组等多个binding时该binding的顺序，越小越靠前，默认为0，相同时按注册的顺序
+func Order(order int) Option {
+	return func(b *binding) error {
+		b.order = order
+		return nil
+	}
+}
+
+//Values 指定这个构造函数中注入配置项的参数对应的key，配置项的值会被转换为参数的类型
+func Values(values map[int]string) Option {
+	return func(b *binding) error {
+		b.values = values
+		return nil
+	}
+}
+
+//DependsOnGroup 指定这个构造函数中切片类型的参数依赖的分组，参数会得到分组中所有的实例
+func DependsOnGroup(groups map[int]string) Option {
+	return func(b *binding) error {
+		b.groupDependsOn = groups
+		return nil
+	}
+}
+
 type ResolveOption func(*resolveOption) error
 type resolveOption struct {
 	name      string
 	args      map[int]interface{}
 	dependsOn map[int]string
+	groups    map[int]string
+	values    map[int]string
+}
+
+// injection 获得函数第i个参数的注入方式
+func (o *resolveOption) injection(i int) injection {
+	return injection{name: o.dependsOn[i], group: o.groups[i], value: o.values[i]}
 }
 
 //Arguments 指定在获得某接口的实例时，该实例构造函数的值
@@ -120,3 +194,19 @@ func CallDependsOn(dependsOn map[int]string) CallOption {
 		return nil
 	}
 }
+
+//CallDependsOnGroup 指定这个函数中切片类型的参数依赖的分组
+func CallDependsOnGroup(groups map[int]string) CallOption {
+	return func(option *resolveOption) error {
+		option.groups = groups
+		return nil
+	}
+}
+
+//CallValues 指定这个函数中注入配置项的参数对应的key
+func CallValues(values map[int]string) CallOption {
+	return func(option *resolveOption) error {
+		option.values = values
+		return nil
+	}
+}
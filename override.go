@@ -0,0 +1,133 @@
+package iocgo
+
+import (
+	"reflect"
+)
+
+// Override 用instance临时替换本容器中接口的binding，返回的restore函数恢复替换前的binding，主要用于测试。
+// 通过Name指定名字时替换同名的binding，否则替换默认binding，替换期间带有条件的binding不参与解析。
+// instance由调用者管理，容器Close时不会释放它。替换之前已经构造的依赖该接口的单例不受影响，
+// 替换期间本容器构造的单例在恢复时被丢弃，之后再获得时重新构造，这样它们不会继续持有instance，
+// 丢弃的实例仍然在容器Close时释放。子容器中构造的单例不会被丢弃。
+// 替换期间为该接口注册的其他binding会在恢复时被丢弃
+func (c *Container) Override(interfacePtr interface{}, instance interface{}, options ...Option) (restore func(), err error) {
+	t, err := getTypeFromInterface(interfacePtr)
+	if err != nil {
+		return nil, err
+	}
+	b := &binding{instance: instance, owner: c, seq: nextRegistration(), externallyOwned: true}
+	for _, op := range options {
+		if err := op(b); err != nil {
+			return nil, err
+		}
+	}
+	b.abstract = t
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	previous, had := c.bind[t]
+	conditional, hadConditional := c.conditional[t]
+	delete(c.conditional, t)
+	if had {
+		nb := previous.copy()
+		nb.addNewBinding(b, b.isDefault || b.name == "" || nb.namedBinding[b.name] == nb.defaultBinding)
+		c.bind[t] = nb
+	} else {
+		c.bind[t] = newNamedBinding(b)
+	}
+	c.addToGroups(b)
+	//记录替换前子容器单独构造的父容器单例，恢复时丢弃替换期间构造的
+	slots := make(map[*binding]bool, len(c.overridden))
+	for k := range c.overridden {
+		slots[k] = true
+	}
+	calls := make(map[*sharedCall]bool, len(c.overriddenCalls))
+	for k := range c.overriddenCalls {
+		calls[k] = true
+	}
+	return func() {
+		c.mu.Lock()
+		if had {
+			c.bind[t] = previous
+		} else {
+			delete(c.bind, t)
+		}
+		if hadConditional {
+			c.conditional[t] = conditional
+		}
+		for _, group := range b.groups {
+			members := c.groups[group][:0:0]
+			for _, m := range c.groups[group] {
+				if m != b {
+					members = append(members, m)
+				}
+			}
+			c.groups[group] = members
+		}
+		for k := range c.overridden {
+			if !slots[k] {
+				delete(c.overridden, k)
+			}
+		}
+		for k := range c.overriddenCalls {
+			if !calls[k] {
+				delete(c.overriddenCalls, k)
+			}
+		}
+		c.mu.Unlock()
+		c.resetSince(b.seq)
+	}, nil
+}
+
+// resetSince 丢弃本容器在seq之后构造的单例，持有binding的锁时不能持有c.mu，所以在恢复binding之后调用
+func (c *Container) resetSince(seq uint64) {
+	tracker := c
+	if c.tracker != nil {
+		tracker = c.tracker
+	}
+	tracker.lifeMu.Lock()
+	resolved := append([]resolvedInstance(nil), tracker.resolved...)
+	tracker.lifeMu.Unlock()
+	for _, r := range resolved {
+		if r.seq > seq && (r.binding.owner == tracker || r.binding.owner.tracker == tracker) && r.binding.constructor != nil {
+			r.binding.reset()
+		}
+	}
+}
+
+// observer 是通过Observe注册的观察者
+type observer struct {
+	fn func(t reflect.Type, name string)
+}
+
+// Observe 注册一个观察者，每次通过本容器或者子容器获得某个binding的实例时都会被调用，
+// 参数是binding对应的接口类型和名字。返回的函数用于取消观察，主要用于在测试中检查哪些binding被获得过
+func (c *Container) Observe(fn func(t reflect.Type, name string)) (cancel func()) {
+	o := &observer{fn: fn}
+	c.mu.Lock()
+	c.observers = append(c.observers, o)
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		observers := c.observers[:0:0]
+		for _, other := range c.observers {
+			if other != o {
+				observers = append(observers, other)
+			}
+		}
+		c.observers = observers
+	}
+}
+
+// notify 通知本容器以及父容器的观察者获得了binding b的实例
+func (c *Container) notify(b *binding) {
+	var observers []*observer
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		observers = append(observers, cur.observers...)
+		cur.mu.RUnlock()
+	}
+	for _, o := range observers {
+		o.fn(b.abstract, b.name)
+	}
+}
@@ -0,0 +1,85 @@
+package iocgo
+
+import (
+	"reflect"
+	"strings"
+)
+
+// In 嵌入到struct中，使该struct成为参数对象。构造函数、装饰器以及Call的函数中参数对象类型的参数，
+// 其导出的字段会分别从容器中获得，字段可以通过以下标签指定依赖的方式：
+//
+//	name:"baz"        依赖的binding的名字，与DependsOn相同
+//	optional:"true"   找不到时设置为零值，与Optional相同
+//	group:"handlers"  依赖的分组，与DependsOnGroup相同
+//	value:"db.dsn"    注入的配置项，与Values相同
+//
+// 例如：
+//
+//	type FoobarParams struct {
+//	    iocgo.In
+//	    Foo Fooer `name:"foo"`
+//	    Bar Barer `optional:"true"`
+//	}
+//	func NewFoobar(p FoobarParams) Foobarer
+type In struct{}
+
+var inType = reflect.TypeOf(In{})
+
+// isParameterObject 判断t是不是嵌入了In的参数对象
+func isParameterObject(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && embeds(t, inType)
+}
+
+// embeds 判断struct类型t是否直接嵌入了marker
+func embeds(t reflect.Type, marker reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// objectFields 遍历参数对象中需要从容器获得的字段
+func objectFields(t reflect.Type, marker reflect.Type, visit func(i int, field reflect.StructField, inj injection)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type == marker || field.PkgPath != "" { //跳过标记和未导出的字段
+			continue
+		}
+		visit(i, field, injection{
+			name:     field.Tag.Get("name"),
+			optional: strings.ToLower(field.Tag.Get("optional")) == "true",
+			group:    field.Tag.Get("group"),
+			value:    field.Tag.Get("value"),
+		})
+	}
+}
+
+// parameterObject 创建类型为t的参数对象，并从容器获得其中的每个字段
+func (c *Container) parameterObject(r *resolution, t reflect.Type) (reflect.Value, error) {
+	obj := reflect.New(t).Elem()
+	var err error
+	objectFields(t, inType, func(i int, field reflect.StructField, inj injection) {
+		if err != nil {
+			return
+		}
+		var v reflect.Value
+		if v, err = c.argument(r, field.Type, inj); err == nil {
+			obj.Field(i).Set(v)
+		}
+	})
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return obj, nil
+}
+
+// parameterObjectDependencies 描述参数对象中每个字段的依赖，index是参数对象在函数中的位置
+func (c *Container) parameterObjectDependencies(index int, t reflect.Type) []dependency {
+	var deps []dependency
+	objectFields(t, inType, func(_ int, field reflect.StructField, inj injection) {
+		deps = append(deps, c.dependencyOf(index, field.Type, inj)...)
+	})
+	return deps
+}
@@ -0,0 +1,130 @@
+package iocgo
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ProfilesEnv 是指定激活的profile的环境变量，多个profile以逗号分隔
+const ProfilesEnv = "IOCGO_PROFILES"
+
+// condition 判断binding是否参与解析
+type condition func(b *binding) bool
+
+// Profile 指定binding只在其中某个profile被激活时参与解析，profile通过ActivateProfiles或者环境变量IOCGO_PROFILES激活。
+// 带有Profile或者条件的binding与同名的binding可以共存，参与解析时优先于没有条件的binding，
+// 多个同时参与解析时后注册的优先
+func Profile(profiles ...string) Option {
+	return func(b *binding) error {
+		b.profiles = append(b.profiles, profiles...)
+		return nil
+	}
+}
+
+// ConditionalOnMissing 指定binding只在容器中没有interfacePtr对应接口的其他binding时参与解析，
+// 只考虑没有条件的binding以及在它之前注册的带有条件的binding，通常用来注册默认实现
+func ConditionalOnMissing(interfacePtr interface{}) Option {
+	return func(b *binding) error {
+		t, err := getTypeFromInterface(interfacePtr)
+		if err != nil {
+			return err
+		}
+		b.conditions = append(b.conditions, func(self *binding) bool {
+			return !self.owner.hasBindingBefore(t, self.seq)
+		})
+		return nil
+	}
+}
+
+// When 指定binding只在predicate返回true时参与解析，predicate的参数是注册binding的容器
+func When(predicate func(*Container) bool) Option {
+	return func(b *binding) error {
+		b.conditions = append(b.conditions, func(self *binding) bool {
+			return predicate(self.owner)
+		})
+		return nil
+	}
+}
+
+// ActivateProfiles 激活profile，对本容器及其子容器中注册的binding生效
+func (c *Container) ActivateProfiles(profiles ...string) {
+	c.mu.Lock()
+	c.profiles = append(c.profiles, profiles...)
+	c.mu.Unlock()
+}
+
+// ActiveProfiles 获得本容器以及父容器中激活的profile，包括环境变量IOCGO_PROFILES指定的profile
+func (c *Container) ActiveProfiles() []string {
+	var profiles []string
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		profiles = append(profiles, cur.profiles...)
+		cur.mu.RUnlock()
+	}
+	for _, p := range strings.Split(os.Getenv(ProfilesEnv), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles
+}
+
+// isConditional 判断binding是否带有Profile或者条件
+func (b *binding) isConditional() bool {
+	return len(b.profiles) > 0 || len(b.conditions) > 0
+}
+
+// isActive 判断binding是否参与解析，没有Profile和条件的binding总是参与解析
+func (b *binding) isActive() bool {
+	if len(b.profiles) > 0 {
+		active := false
+		for _, p := range b.owner.ActiveProfiles() {
+			for _, want := range b.profiles {
+				active = active || p == want
+			}
+		}
+		if !active {
+			return false
+		}
+	}
+	for _, cond := range b.conditions {
+		if !cond(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// activeConditionals 获得本容器中参与解析的带有条件的binding，后注册的在前
+func (c *Container) activeConditionals(t reflect.Type) []*binding {
+	c.mu.RLock()
+	candidates := c.conditional[t]
+	c.mu.RUnlock()
+	var active []*binding
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if candidates[i].isActive() {
+			active = append(active, candidates[i])
+		}
+	}
+	return active
+}
+
+// hasBindingBefore 判断本容器或者父容器中是否有接口t的没有条件的binding，或者在seq之前注册并参与解析的带有条件的binding
+func (c *Container) hasBindingBefore(t reflect.Type, seq uint64) bool {
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		nb, ok := cur.bind[t]
+		candidates := cur.conditional[t]
+		cur.mu.RUnlock()
+		if ok && len(nb.namedBinding) > 0 {
+			return true
+		}
+		for _, b := range candidates {
+			if b.seq < seq && b.isActive() {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package iocgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PropertySource 是配置项的来源，Property返回key对应的配置项的值，不存在时返回false。
+// 构造函数中通过Values指定的参数以及Fill中带有`value:"key"`标签的字段会从容器的配置项来源中获得值，
+// 并转换为参数或字段的类型，支持string、bool、整数、浮点数、time.Duration以及以逗号分隔的这些类型的切片
+type PropertySource interface {
+	Property(key string) (string, bool)
+}
+
+// MapSource 是内存中的配置项来源
+type MapSource map[string]string
+
+func (m MapSource) Property(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// EnvSource 是从环境变量获得配置项的来源，key转换为大写，并将.和-替换为_，
+// 如果指定了Prefix，再加上Prefix_作为前缀，比如Prefix为APP时，db.dsn对应的环境变量为APP_DB_DSN
+type EnvSource struct {
+	Prefix string
+}
+
+// NewEnvSource 创建一个从环境变量获得配置项的来源
+func NewEnvSource(prefix string) *EnvSource {
+	return &EnvSource{Prefix: prefix}
+}
+
+func (e *EnvSource) Property(key string) (string, bool) {
+	name := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+	if e.Prefix != "" {
+		name = strings.ToUpper(e.Prefix) + "_" + name
+	}
+	return os.LookupEnv(name)
+}
+
+// NewJSONSource 从JSON创建配置项来源，嵌套的对象展开为以.连接的key，
+// 比如{"db":{"dsn":"..."}}对应的key为db.dsn，数组的值以逗号连接，其中的元素也可以通过key.0这样的下标获得
+func NewJSONSource(data []byte) (MapSource, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	m := make(MapSource)
+	flatten(m, "", v)
+	return m, nil
+}
+
+// NewJSONFileSource 从JSON文件创建配置项来源
+func NewJSONFileSource(path string) (MapSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONSource(data)
+}
+
+func flatten(m MapSource, key string, v interface{}) {
+	join := func(k string) string {
+		if key == "" {
+			return k
+		}
+		return key + "." + k
+	}
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			flatten(m, join(k), item)
+		}
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for i, item := range value {
+			flatten(m, join(strconv.Itoa(i)), item)
+			items = append(items, fmt.Sprint(item))
+		}
+		if key != "" {
+			m[key] = strings.Join(items, ",")
+		}
+	case nil:
+	default:
+		if key != "" {
+			m[key] = fmt.Sprint(value)
+		}
+	}
+}
+
+// AddPropertySource 添加一个配置项来源，后添加的来源优先，本容器中找不到的配置项会到父容器中找
+func (c *Container) AddPropertySource(source PropertySource) {
+	c.mu.Lock()
+	c.properties = append(c.properties, source)
+	c.mu.Unlock()
+}
+
+// Property 从配置项来源中获得key对应的值
+func (c *Container) Property(key string) (string, bool) {
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		sources := cur.properties
+		cur.mu.RUnlock()
+		for i := len(sources) - 1; i >= 0; i-- {
+			if v, ok := sources[i].Property(key); ok {
+				return v, true
+			}
+		}
+	}
+	return "", false
+}
+
+// propertyValue 获得key对应的配置项，并转换为类型t
+func (c *Container) propertyValue(r *resolution, t reflect.Type, key string, optional bool) (reflect.Value, error) {
+	s, ok := c.Property(key)
+	if !ok {
+		if optional {
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, newResolutionError(r.path, "property "+key, ErrNotFound)
+	}
+	v, err := convertProperty(s, t)
+	if err != nil {
+		return reflect.Value{}, newResolutionError(r.path, "property "+key,
+			containerError(ErrInvalidProperty, "%s=%q to %s: %v", key, s, t, err))
+	}
+	return v, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// convertProperty 将配置项的值s转换为类型t
+func convertProperty(s string, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	if t == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(int64(d))
+		return v, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 0, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		v = reflect.MakeSlice(t, 0, 0)
+		if strings.TrimSpace(s) == "" {
+			return v, nil
+		}
+		for _, item := range strings.Split(s, ",") {
+			elem, err := convertProperty(strings.TrimSpace(item), t.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v = reflect.Append(v, elem)
+		}
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type")
+	}
+	return v, nil
+}
@@ -0,0 +1,99 @@
+package iocgo
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Out 嵌入到struct中，使该struct成为结果对象。构造函数返回结果对象时，其每个导出的字段都会注册为一个单独的binding，
+// 字段的类型就是binding对应的类型，可以通过以下标签指定：
+//
+//	name:"baz"        binding的名字，与Name相同
+//	group:"handlers"  binding所属的分组，与Group相同
+//
+// 例如：
+//
+//	type ClientResult struct {
+//	    iocgo.Out
+//	    Client  Client
+//	    Metrics MetricsSink `name:"client"`
+//	    Health  HealthCheck `group:"health"`
+//	}
+//	func NewClient() (ClientResult, error)
+//
+// 单例的结果对象只会调用一次构造函数，所有字段的binding共享这次调用的结果
+type Out struct{}
+
+var outType = reflect.TypeOf(Out{})
+
+// isResultObject 判断t是不是嵌入了Out的结果对象
+func isResultObject(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && embeds(t, outType)
+}
+
+// sharedCall 保存同一个构造函数注册的多个binding共享的一次调用结果
+type sharedCall struct {
+	mu      sync.Mutex
+	done    bool
+	results []interface{}
+}
+
+func (s *sharedCall) get(call func() ([]interface{}, error)) ([]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return s.results, nil
+	}
+	results, err := call()
+	if err != nil {
+		return nil, err
+	}
+	s.results, s.done = results, true
+	return results, nil
+}
+
+// reset 清除调用结果，之后再获得实例时会重新调用构造函数
+func (s *sharedCall) reset() {
+	s.mu.Lock()
+	s.results, s.done = nil, false
+	s.mu.Unlock()
+}
+
+func (s *sharedCall) clone() *sharedCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &sharedCall{done: s.done, results: s.results}
+}
+
+// result 从构造函数的返回值中取出binding对应的实例
+func (b *binding) result(results []interface{}) interface{} {
+	inst := results[b.output]
+	if b.field == nil || inst == nil {
+		return inst
+	}
+	return reflect.ValueOf(inst).FieldByIndex(b.field).Interface()
+}
+
+// resultBindings 为结果对象类型t的每个导出字段创建binding，newBinding创建应用了注册选项的binding
+func resultBindings(t reflect.Type, output int, call *sharedCall, newBinding func() (*binding, error)) ([]*binding, error) {
+	var bindings []*binding
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type == outType || field.PkgPath != "" { //跳过标记和未导出的字段
+			continue
+		}
+		b, err := newBinding()
+		if err != nil {
+			return nil, err
+		}
+		if name, ok := field.Tag.Lookup("name"); ok {
+			b.name = name
+		}
+		if group, ok := field.Tag.Lookup("group"); ok {
+			b.groups = append(append([]string(nil), b.groups...), group)
+		}
+		b.abstract, b.output, b.field, b.call = field.Type, output, field.Index, call
+		bindings = append(bindings, b)
+	}
+	return bindings, nil
+}
@@ -0,0 +1,113 @@
+package iocgo
+
+import (
+	"context"
+	"sync"
+)
+
+// resolution 记录一次解析过程中的上下文状态
+type resolution struct {
+	scope *Scope          //当前解析所在的作用域，在容器中直接解析时为nil
+	path  []*binding      //正在解析中的binding链，用于检测循环依赖
+	ctx   context.Context //调用者传入的context，为nil时表示没有传入
+	//解析链上最后的binding是否已经构造完成，延迟获取的依赖据此决定是否沿用解析链，通过原子操作访问
+	finished int32
+}
+
+// enter 进入binding的解析，如果该binding已经在解析链中，说明存在循环依赖
+func (r *resolution) enter(b *binding) (*resolution, error) {
+	for i, p := range r.path {
+		//同一个构造函数注册的binding共享调用，也构成循环
+		if p == b || (b.call != nil && p.call == b.call) {
+			return nil, newCycleError(append(r.path[i:len(r.path):len(r.path)], b))
+		}
+	}
+	path := make([]*binding, len(r.path), len(r.path)+1)
+	copy(path, r.path)
+	return &resolution{scope: r.scope, path: append(path, b), ctx: r.ctx}, nil
+}
+
+// withoutScope 返回不在作用域中的解析状态，用于构造单例，单例的依赖不能是作用域对象，
+// 单例也不能持有调用者的ctx，否则调用者的请求结束后单例持有的是已经取消的ctx
+func (r *resolution) withoutScope() *resolution {
+	return &resolution{path: r.path, ctx: detach(r.ctx)}
+}
+
+// Scope 是容器的一个作用域，通过Scoped注册的对象在每个Scope中只构造一次，
+// 单例对象与容器共享。Scope结束时需要调用Close释放作用域内构造的对象
+type Scope struct {
+	container *Container
+	instances map[*binding]*instanceSlot
+	calls     map[*sharedCall]*sharedCall //同一个构造函数注册的多个binding在作用域中共享的调用结果
+	resolved  []resolvedInstance
+	mu        sync.Mutex
+}
+
+// NewScope 创建一个新的作用域，比如每个HTTP请求或者每个任务一个作用域
+func (c *Container) NewScope() *Scope {
+	return &Scope{
+		container: c,
+		instances: make(map[*binding]*instanceSlot),
+		calls:     make(map[*sharedCall]*sharedCall),
+	}
+}
+
+// slot 获得binding在作用域中对应的实例
+func (s *Scope) slot(b *binding) *instanceSlot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, ok := s.instances[b]
+	if !ok {
+		slot = &instanceSlot{}
+		s.instances[b] = slot
+	}
+	return slot
+}
+
+// sharedCall 获得同一个构造函数注册的多个binding在作用域中共享的调用结果
+func (s *Scope) sharedCall(b *binding) *sharedCall {
+	if b.call == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	call, ok := s.calls[b.call]
+	if !ok {
+		call = &sharedCall{}
+		s.calls[b.call] = call
+	}
+	return call
+}
+
+// track 记录作用域中新构造的实例，用于Close时按逆序释放
+func (s *Scope) track(b *binding, instance interface{}) {
+	s.mu.Lock()
+	s.resolved = append(s.resolved, resolvedInstance{binding: b, instance: instance})
+	s.mu.Unlock()
+}
+
+// Resolve 在作用域中获得接口对应的实例，用法与Container.Resolve相同
+func (s *Scope) Resolve(abstraction interface{}, options ...ResolveOption) error {
+	return s.container.resolve(&resolution{scope: s}, abstraction, options...)
+}
+
+// Call 在作用域中调用函数，用法与Container.Call相同
+func (s *Scope) Call(function interface{}, options ...CallOption) ([]interface{}, error) {
+	return s.container.call(&resolution{scope: s}, function, options...)
+}
+
+// Fill 在作用域中填充struct的字段，用法与Container.Fill相同
+func (s *Scope) Fill(structure interface{}) error {
+	return s.container.fill(&resolution{scope: s}, structure)
+}
+
+// Close 按创建的逆序释放作用域内构造的对象，容器中的单例不受影响
+func (s *Scope) Close(ctx context.Context) error {
+	s.mu.Lock()
+	resolved := s.resolved
+	s.resolved = nil
+	s.instances = make(map[*binding]*instanceSlot)
+	s.calls = make(map[*sharedCall]*sharedCall)
+	s.mu.Unlock()
+	return disposeAll(ctx, resolved)
+}
@@ -0,0 +1,105 @@
+package iocgo
+
+import (
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// RegisterType 注册一个struct类型，容器会为它生成构造函数，不需要手写NewXxx。
+// prototype是struct或者struct的指针，构造函数返回相同的类型，每个新实例都是prototype的浅拷贝，
+// prototype为nil指针时从零值开始。struct的字段按照Fill的规则从容器获得：
+//
+//	interface类型的字段          获得对应的binding，可以通过name和optional标签指定
+//	interface的切片或map类型的字段 获得所有的binding，可以通过optional标签指定
+//	group:"handlers"            切片类型的字段获得分组中所有的实例
+//	value:"db.dsn"              字段获得配置项的值
+//
+// 其他字段保持prototype中的值。生成的构造函数的参数按字段的顺序排列，可以与Interface、Name、Lifestyle等选项一起使用：
+//
+//	var fb Foobarer
+//	container.RegisterType(&Foobar{}, Interface(&fb))
+func (c *Container) RegisterType(prototype interface{}, options ...Option) error {
+	t := reflect.TypeOf(prototype)
+	if t == nil || t.Kind() != reflect.Struct && (t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct) {
+		return containerError(ErrInvalidConstructor, "the prototype must be a struct or a struct pointer, input type: %v", t)
+	}
+	constructor, injections := typeConstructor(reflect.ValueOf(prototype))
+	//标签描述的注入方式在选项之后合并，同一个参数以Optional、DependsOn等选项的指定为准
+	return c.Register(constructor, append(options, injections)...)
+}
+
+// typeConstructor 为prototype的类型生成构造函数，以及描述构造函数参数如何注入的选项
+func typeConstructor(prototype reflect.Value) (interface{}, Option) {
+	t := prototype.Type()
+	structType := t
+	if t.Kind() == reflect.Ptr {
+		structType = t.Elem()
+	}
+	var (
+		in       []reflect.Type
+		fields   []int
+		optional = make(map[int]bool)
+		names    = make(map[int]string)
+		groups   = make(map[int]string)
+		values   = make(map[int]string)
+	)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		index := len(in)
+		if key, ok := field.Tag.Lookup("value"); ok {
+			values[index] = key
+		} else if group, ok := field.Tag.Lookup("group"); ok && field.Type.Kind() == reflect.Slice {
+			groups[index] = group
+		} else if field.Type.Kind() == reflect.Interface {
+			if name := field.Tag.Get("name"); name != "" {
+				names[index] = name
+			}
+		} else if !isInterfaceSlice(field.Type) && !isInterfaceMap(field.Type) { //只有这些字段从容器获得
+			continue
+		}
+		if strings.ToLower(field.Tag.Get("optional")) == "true" {
+			optional[index] = true
+		}
+		in = append(in, field.Type)
+		fields = append(fields, i)
+	}
+	fnType := reflect.FuncOf(in, []reflect.Type{t}, false)
+	constructor := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		inst := reflect.New(structType)
+		if t.Kind() == reflect.Struct {
+			inst.Elem().Set(prototype)
+		} else if !prototype.IsNil() {
+			inst.Elem().Set(prototype.Elem())
+		}
+		s := inst.Elem()
+		for i, arg := range args {
+			f := s.Field(fields[i])
+			//未导出的字段与Fill一样通过unsafe赋值
+			reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem().Set(arg)
+		}
+		if t.Kind() == reflect.Struct {
+			return []reflect.Value{s}
+		}
+		return []reflect.Value{inst}
+	})
+	return constructor.Interface(), func(b *binding) error {
+		b.optionalIndexes = mergeInjections(b.optionalIndexes, optional)
+		b.dependsOn = mergeInjections(b.dependsOn, names)
+		b.groupDependsOn = mergeInjections(b.groupDependsOn, groups)
+		b.values = mergeInjections(b.values, values)
+		return nil
+	}
+}
+
+// mergeInjections 将字段标签描述的注入方式合并到选项指定的注入方式中，返回新的map，同一个参数以选项的指定为准
+func mergeInjections[V any](specified, tagged map[int]V) map[int]V {
+	merged := make(map[int]V, len(specified)+len(tagged))
+	for i, v := range tagged {
+		merged[i] = v
+	}
+	for i, v := range specified {
+		merged[i] = v
+	}
+	return merged
+}
@@ -0,0 +1,147 @@
+package iocgo
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Validate 不调用任何构造函数，检查容器中所有binding的构造函数参数是否都能被满足，
+// 会考虑Parameters、DependsOn、Optional、RegisterSubInterface以及默认binding。
+// 只通过工厂获得的binding，其构造函数中由工厂调用时传入的参数不需要从容器获得。
+// 找不到的依赖、单例依赖了作用域对象以及循环依赖会被一起返回，多个问题时返回MultiError
+func (c *Container) Validate() error {
+	var errs []error
+	bindings := c.allBindings()
+	assisted := c.assistedBy(bindings)
+	for _, b := range bindings {
+		errs = append(errs, c.validateBinding(b, assisted[b])...)
+	}
+	if err := c.CheckCycles(); err != nil {
+		if multi, ok := err.(MultiError); ok {
+			errs = append(errs, multi...)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+	return newMultiError(errs)
+}
+
+// assistedBy 获得每个被工厂依赖的binding中由工厂调用时传入的构造函数参数。
+// binding被多个工厂依赖或者还被直接依赖时，只有每一处都会传入的参数才不需要从容器获得
+func (c *Container) assistedBy(bindings []*binding) map[*binding]map[int]bool {
+	assisted := make(map[*binding]map[int]bool)
+	for _, b := range bindings {
+		resolver := b.resolver(c)
+		for _, dep := range b.dependencies(resolver) {
+			dbs, err := resolver.dependencyBindings(dep)
+			if err != nil {
+				continue
+			}
+			for _, db := range dbs {
+				indexes := make(map[int]bool)
+				if dep.lazy && db.constructor != nil {
+					for _, i := range assistedIndexes(reflect.TypeOf(db.constructor), dep.args) {
+						if i >= 0 {
+							indexes[i] = true
+						}
+					}
+				}
+				if prev, ok := assisted[db]; ok {
+					for i := range prev {
+						if !indexes[i] {
+							delete(prev, i)
+						}
+					}
+				} else {
+					assisted[db] = indexes
+				}
+			}
+		}
+	}
+	return assisted
+}
+
+// validateBinding 检查binding的依赖，assisted中的构造函数参数由工厂传入，不需要检查
+func (c *Container) validateBinding(b *binding, assisted map[int]bool) []error {
+	c = b.resolver(c)
+	var errs []error
+	fail := func(missing string, err error) {
+		errs = append(errs, &ResolutionError{Type: b.abstract, Name: b.name, Path: []string{b.String(), missing}, Err: err})
+	}
+	for _, dep := range b.dependencies(c) {
+		if !dep.decorator && assisted[dep.index] {
+			continue
+		}
+		if dep.value != "" {
+			if _, ok := c.Property(dep.value); !ok && !dep.optional {
+				fail(dep.describe(), ErrNotFound)
+			}
+			continue
+		}
+		dbs, err := c.dependencyBindings(dep)
+		if err != nil {
+			if !dep.optional {
+				fail(dep.describe(), err)
+			}
+			continue
+		}
+		for _, db := range dbs {
+			if db.isScoped && !b.isScoped && !b.isTransient {
+				fail(db.String(), ErrNoScope)
+			}
+			//不返回error的工厂在构造失败时只能panic
+			if dep.panics && db.mayFail() {
+				fail(db.String(), containerError(ErrInvalidFunction,
+					"parameter %d returns %s without error, but its constructor may fail, use (%s, error) instead", dep.index, dep.abstract, dep.abstract))
+			}
+		}
+	}
+	//通过Parameters指定的struct指针参数会被Fill
+	for _, v := range b.specifiedParameters {
+		t := reflect.TypeOf(v)
+		if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct || isNil(v) {
+			continue
+		}
+		for _, missing := range c.missingFields(t.Elem()) {
+			fail(missing, ErrNotFound)
+		}
+	}
+	return errs
+}
+
+// missingFields 按照Fill的规则检查struct的字段，返回无法填充的字段的描述
+func (c *Container) missingFields(t reflect.Type) []string {
+	var missing []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fType := field.Type
+		if key, ok := field.Tag.Lookup("value"); ok {
+			if _, found := c.Property(key); !found && strings.ToLower(field.Tag.Get("optional")) != "true" {
+				missing = append(missing, t.String()+"."+field.Name+" property "+key)
+			}
+			continue
+		}
+		sliceFill := false
+		if _, isGroup := field.Tag.Lookup("group"); isGroup && fType.Kind() == reflect.Slice {
+			continue //分组为空时得到空切片
+		}
+		if isInterfaceSlice(fType) || isInterfaceMap(fType) {
+			sliceFill = true
+			fType = fType.Elem()
+		} else if fType.Kind() != reflect.Interface {
+			continue
+		}
+		if strings.ToLower(field.Tag.Get("optional")) == "true" {
+			continue
+		}
+		name := field.Tag.Get("name")
+		if sliceFill {
+			if len(c.namedBindings(fType)) == 0 {
+				missing = append(missing, t.String()+"."+field.Name+" "+field.Type.String())
+			}
+		} else if _, err := c.getBinding(fType, name); err != nil {
+			missing = append(missing, t.String()+"."+field.Name+" "+describe(fType, name))
+		}
+	}
+	return missing
+}